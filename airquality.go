@@ -0,0 +1,127 @@
+package daikin
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	aqHistoryHours = 24
+	aqHistoryDays  = 7
+)
+
+// AirQualitySample covers the hour or day starting at Time.
+type AirQualitySample struct {
+	Time time.Time
+	Avg  int
+	Max  int
+}
+
+// AirQualitySeries is ordered from oldest to newest.
+type AirQualitySeries []AirQualitySample
+
+type AirQualityHistory struct {
+	End             time.Time
+	Level           AirQualityLevel
+	ParticlesLevel  AirQualityLevel
+	VOCLevel        AirQualityLevel
+	ParticlesHourly AirQualitySeries
+	ParticlesDaily  AirQualitySeries
+	VOCHourly       AirQualitySeries
+	VOCDaily        AirQualitySeries
+}
+
+// AirQualityHistory returns nil when the device has no indoor air quality
+// sensor or no time to date the history by. Hour1 is the hour ending at
+// IAQhistoryEndDate and Day1 is the day it falls on, in the device time zone.
+// IAQIndoorTimeStamp is used when the end date is not set.
+func (di *DeviceInfo) AirQualityHistory() *AirQualityHistory {
+	if !di.AqIndoorAvailable {
+		return nil
+	}
+
+	end := int64(di.IAQhistoryEndDate)
+	if end == 0 {
+		end = int64(di.IAQIndoorTimeStamp)
+	}
+	if end == 0 {
+		return nil
+	}
+
+	h := &AirQualityHistory{
+		End:            time.Unix(end, 0).In(di.Location()),
+		Level:          AirQualityLevel(di.AqIndoorLevel),
+		ParticlesLevel: AirQualityLevel(di.AqIndoorParticlesLevel),
+		VOCLevel:       AirQualityLevel(di.AqIndoorVOCLevel),
+	}
+
+	h.ParticlesHourly = di.aqHourly("AqIndoorParticlesValue", h.End)
+	h.ParticlesDaily = di.aqDaily("AqIndoorParticlesValue", h.End)
+	h.VOCHourly = di.aqHourly("AqIndoorVOCValue", h.End)
+	h.VOCDaily = di.aqDaily("AqIndoorVOCValue", h.End)
+
+	return h
+}
+
+func (di *DeviceInfo) aqHourly(base string, end time.Time) AirQualitySeries {
+	series := make(AirQualitySeries, 0, aqHistoryHours)
+	for n := aqHistoryHours; n >= 1; n-- {
+		series = append(series, di.aqSample(base+"Hour"+strconv.Itoa(n), end.Add(-time.Duration(n)*time.Hour)))
+	}
+	return series
+}
+
+func (di *DeviceInfo) aqDaily(base string, end time.Time) AirQualitySeries {
+	midnight := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, end.Location())
+
+	series := make(AirQualitySeries, 0, aqHistoryDays)
+	for n := aqHistoryDays; n >= 1; n-- {
+		series = append(series, di.aqSample(base+"Day"+strconv.Itoa(n), midnight.AddDate(0, 0, 1-n)))
+	}
+	return series
+}
+
+func (di *DeviceInfo) aqSample(name string, at time.Time) AirQualitySample {
+	return AirQualitySample{
+		Time: at,
+		Avg:  di.intField(name + "Avg"),
+		Max:  di.intField(name + "Max"),
+	}
+}
+
+// Worst returns the sample with the highest maximum. Ties go to the most
+// recent sample.
+func (s AirQualitySeries) Worst() (AirQualitySample, bool) {
+	if len(s) == 0 {
+		return AirQualitySample{}, false
+	}
+
+	worst := s[0]
+	for _, sample := range s[1:] {
+		if sample.Max >= worst.Max {
+			worst = sample
+		}
+	}
+	return worst, true
+}
+
+// Trend is the least squares slope of the averages, per sample. A positive
+// trend means air quality is getting worse.
+func (s AirQualitySeries) Trend() float64 {
+	n := float64(len(s))
+	if n < 2 {
+		return 0
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+	for i, sample := range s {
+		x := float64(i)
+		y := float64(sample.Avg)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	return (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+}
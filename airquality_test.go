@@ -0,0 +1,79 @@
+package daikin_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nbio/st"
+	"github.com/redgoose/daikin-skyport"
+)
+
+func TestAirQualityHistoryFixture(t *testing.T) {
+	deviceInfo := loadDeviceInfo(t)
+
+	st.Expect(t, deviceInfo.AirQualityHistory() == nil, true)
+}
+
+func TestAirQualityHistory(t *testing.T) {
+	end := time.Date(2024, 3, 12, 14, 0, 0, 0, time.UTC)
+
+	deviceInfo := &daikin.DeviceInfo{
+		TimeZone:                        "America/New_York",
+		AqIndoorAvailable:               true,
+		AqIndoorLevel:                   2,
+		AqIndoorVOCLevel:                1,
+		IAQhistoryEndDate:               int(end.Unix()),
+		AqIndoorParticlesValueHour1Avg:  12,
+		AqIndoorParticlesValueHour1Max:  30,
+		AqIndoorParticlesValueHour5Avg:  40,
+		AqIndoorParticlesValueHour5Max:  95,
+		AqIndoorParticlesValueHour24Max: 95,
+		AqIndoorVOCValueDay1Avg:         300,
+		AqIndoorVOCValueDay2Avg:         250,
+		AqIndoorVOCValueDay3Avg:         200,
+		AqIndoorVOCValueDay7Max:         900,
+	}
+
+	h := deviceInfo.AirQualityHistory()
+	st.Expect(t, h.End.Equal(end), true)
+	st.Expect(t, h.Level, daikin.AirQualityUnhealthySensitive)
+	st.Expect(t, h.VOCLevel.String(), "Moderate")
+	st.Expect(t, h.ParticlesLevel, daikin.AirQualityGood)
+
+	st.Expect(t, len(h.ParticlesHourly), 24)
+	st.Expect(t, h.ParticlesHourly[23].Time.Equal(end.Add(-time.Hour)), true)
+	st.Expect(t, h.ParticlesHourly[23].Avg, 12)
+	st.Expect(t, h.ParticlesHourly[0].Time.Equal(end.Add(-24*time.Hour)), true)
+
+	worst, ok := h.ParticlesHourly.Worst()
+	st.Expect(t, ok, true)
+	st.Expect(t, worst.Time.Equal(end.Add(-5*time.Hour)), true)
+	st.Expect(t, worst.Avg, 40)
+
+	st.Expect(t, len(h.VOCDaily), 7)
+	st.Expect(t, h.VOCDaily[6].Time, time.Date(2024, 3, 12, 0, 0, 0, 0, h.End.Location()))
+	st.Expect(t, h.VOCDaily[0].Time, time.Date(2024, 3, 6, 0, 0, 0, 0, h.End.Location()))
+	st.Expect(t, h.VOCDaily[0].Max, 900)
+	st.Expect(t, h.VOCDaily[4:].Trend(), 50.0)
+}
+
+func TestAirQualityHistoryNoTimestamp(t *testing.T) {
+	deviceInfo := &daikin.DeviceInfo{
+		TimeZone:                       "UTC",
+		AqIndoorAvailable:              true,
+		AqIndoorParticlesValueHour1Avg: 12,
+	}
+	st.Expect(t, deviceInfo.AirQualityHistory() == nil, true)
+
+	now := time.Date(2024, 3, 12, 14, 0, 0, 0, time.UTC)
+	deviceInfo.IAQIndoorTimeStamp = int(now.Unix())
+	st.Expect(t, deviceInfo.AirQualityHistory().End.Equal(now), true)
+}
+
+func TestAirQualitySeriesEmpty(t *testing.T) {
+	var series daikin.AirQualitySeries
+
+	_, ok := series.Worst()
+	st.Expect(t, ok, false)
+	st.Expect(t, series.Trend(), 0.0)
+}
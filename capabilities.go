@@ -0,0 +1,190 @@
+package daikin
+
+import "errors"
+
+// Capabilities describes what the installed system can do. The CtSystemCap
+// fields are only reported by communicating equipment; for other systems
+// Communicating is false and the system is assumed to be able to heat and
+// cool and to have an indoor fan, with emergency heat left to
+// EmergencyHeatMode. Fan is the indoor blower of a furnace or air handler.
+type Capabilities struct {
+	Communicating     bool
+	Cool              bool
+	Heat              bool
+	CompressorHeat    bool
+	GasHeat           bool
+	ElectricHeat      bool
+	EmergencyHeat     bool
+	EmergencyHeatMode bool
+	Humidification    bool
+	Dehumidification  bool
+	Ventilation       bool
+	Fan               bool
+	IAQSensor         bool
+	ComfortMode       bool
+	S21DemandControl  bool
+	UtilityPrice      bool
+	ModeLimit         ModeLimit
+	CoolSetpointMin   Temperature
+	CoolSetpointMax   Temperature
+	HeatSetpointMin   Temperature
+	HeatSetpointMax   Temperature
+}
+
+func (di *DeviceInfo) Capabilities() Capabilities {
+	communicating := di.Outdoor() != nil || di.Furnace() != nil || di.AirHandler() != nil
+
+	c := Capabilities{
+		Communicating:     communicating,
+		Cool:              di.CtSystemCapCool || !communicating,
+		Heat:              di.CtSystemCapHeat || !communicating,
+		CompressorHeat:    di.CtSystemCapCompressorHeat,
+		GasHeat:           di.CtSystemCapGasHeat,
+		ElectricHeat:      di.CtSystemCapElectricHeat,
+		EmergencyHeat:     di.CtSystemCapEmergencyHeat || !communicating,
+		EmergencyHeatMode: di.ModeEmHeatAvailable,
+		Humidification:    di.CtSystemCapHumidification,
+		Dehumidification:  di.CtSystemCapDehumidification,
+		Ventilation:       di.CtSystemCapVentilation,
+		Fan:               di.Furnace() != nil || di.AirHandler() != nil || !communicating,
+		IAQSensor:         di.CtIAQsensorExists,
+		ComfortMode:       di.S21ComfortModeExists,
+		S21DemandControl:  boardPresent(di.S21IndoorUnitType),
+		UtilityPrice:      di.AdrPrice != adrPriceNotAvailable,
+		ModeLimit:         ModeLimit(di.ModeLimit),
+		CoolSetpointMin:   Celsius(di.TempSPMin),
+		CoolSetpointMax:   Celsius(di.TempSPMax),
+		HeatSetpointMin:   Celsius(di.TempSPMin),
+		HeatSetpointMax:   Celsius(di.TempSPMax),
+	}
+
+	// the equipment protocol limits narrow the thermostat range when set
+	if v := Celsius(di.EquipProtocolMinCoolSetpoint); v != 0 && v > c.CoolSetpointMin {
+		c.CoolSetpointMin = v
+	}
+	if v := Celsius(di.EquipProtocolMaxCoolSetpoint); v != 0 && v < c.CoolSetpointMax {
+		c.CoolSetpointMax = v
+	}
+	if v := Celsius(di.EquipProtocolMinHeatSetpoint); v != 0 && v > c.HeatSetpointMin {
+		c.HeatSetpointMin = v
+	}
+	if v := Celsius(di.EquipProtocolMaxHeatSetpoint); v != 0 && v < c.HeatSetpointMax {
+		c.HeatSetpointMax = v
+	}
+
+	return c
+}
+
+// SupportsMode reports whether the system can run mode within ModeLimit.
+func (c Capabilities) SupportsMode(mode Mode) bool {
+	heat := c.Heat && c.ModeLimit != ModeLimitCoolOnly
+	cool := c.Cool && c.ModeLimit != ModeLimitHeatOnly
+
+	switch mode {
+	case ModeOff:
+		return true
+	case ModeHeat:
+		return heat
+	case ModeCool:
+		return cool
+	case ModeAuto:
+		return heat && cool
+	case ModeEmHeat:
+		return heat && c.EmergencyHeat && c.EmergencyHeatMode
+	}
+	return false
+}
+
+// Modes returns the modes the system supports, in Mode order.
+func (c Capabilities) Modes() []Mode {
+	var modes []Mode
+	for mode := ModeOff; mode <= ModeEmHeat; mode++ {
+		if c.SupportsMode(mode) {
+			modes = append(modes, mode)
+		}
+	}
+	return modes
+}
+
+func (c Capabilities) checkMode(mode Mode) error {
+	if mode > ModeEmHeat {
+		return errors.New("invalid mode")
+	}
+
+	if c.SupportsMode(mode) {
+		return nil
+	}
+
+	if mode == ModeEmHeat {
+		return errors.New("emergency heat is not available on this device")
+	}
+	return errors.New("mode is not supported by this system")
+}
+
+// SupportsSystemTest reports whether the system has the equipment test
+// exercises.
+func (c Capabilities) SupportsSystemTest(test SystemTest) bool {
+	switch test {
+	case SystemTestCool:
+		return c.Cool
+	case SystemTestFan:
+		return c.Fan
+	case SystemTestGasHeat:
+		return c.GasHeat
+	case SystemTestElectricHeat:
+		return c.ElectricHeat
+	case SystemTestHeatPumpHeat:
+		return c.CompressorHeat
+	case SystemTestAuxHeater:
+		return c.CompressorHeat && (c.GasHeat || c.ElectricHeat)
+	case SystemTestHumidifier:
+		return c.Humidification
+	case SystemTestDehumidifier:
+		return c.Dehumidification
+	case SystemTestVentilator:
+		return c.Ventilation
+	}
+	return false
+}
+
+func (c Capabilities) checkFan() error {
+	if !c.Fan {
+		return errors.New("indoor fan is not available on this device")
+	}
+	return nil
+}
+
+// checkFanExtend only checks the extensions that are turned on.
+func (c Capabilities) checkFanExtend(cool, heat bool) error {
+	if err := c.checkFan(); err != nil {
+		return err
+	}
+
+	if cool && !c.Cool {
+		return errors.New("cooling is not available on this device")
+	}
+
+	if heat && !c.Heat {
+		return errors.New("heating is not available on this device")
+	}
+
+	return nil
+}
+
+// checkLockoutSettings only rejects settings the update turns on.
+func (c Capabilities) checkLockoutSettings(update LockoutSettingsUpdate) error {
+	if isTrue(update.EmHeatAvailable) && !c.EmergencyHeat {
+		return errors.New("emergency heat is not available on this device")
+	}
+
+	if c.Communicating && !c.CompressorHeat &&
+		(isTrue(update.HeatPumpLockoutEnabled) || isTrue(update.AuxHeaterHeatPumpLockoutEnabled)) {
+		return errors.New("heat pump lockout requires a heat pump")
+	}
+
+	return nil
+}
+
+func isTrue(b *bool) bool {
+	return b != nil && *b
+}
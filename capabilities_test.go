@@ -0,0 +1,327 @@
+package daikin_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/nbio/st"
+	"github.com/redgoose/daikin-skyport"
+)
+
+func TestCapabilitiesFixture(t *testing.T) {
+	deviceInfo := loadDeviceInfo(t)
+
+	caps := deviceInfo.Capabilities()
+	st.Expect(t, caps.Communicating, true)
+	st.Expect(t, caps.Cool, true)
+	st.Expect(t, caps.CompressorHeat, true)
+	st.Expect(t, caps.GasHeat, true)
+	st.Expect(t, caps.ElectricHeat, false)
+	st.Expect(t, caps.Ventilation, false)
+	st.Expect(t, caps.IAQSensor, false)
+	st.Expect(t, caps.Fan, true)
+	st.Expect(t, caps.S21DemandControl, false)
+	st.Expect(t, caps.UtilityPrice, false)
+	st.Expect(t, caps.ModeLimit, daikin.ModeLimitAll)
+	st.Expect(t, caps.CoolSetpointMin, daikin.Celsius(10))
+	st.Expect(t, caps.HeatSetpointMax, daikin.Celsius(32))
+	st.Expect(t, caps.Modes(), []daikin.Mode{daikin.ModeOff, daikin.ModeHeat, daikin.ModeCool, daikin.ModeAuto, daikin.ModeEmHeat})
+}
+
+func TestCapabilities(t *testing.T) {
+	deviceInfo := &daikin.DeviceInfo{
+		CtOutdoorUnitType:            6,
+		CtSystemCapCool:              true,
+		TempSPMin:                    10,
+		TempSPMax:                    32,
+		EquipProtocolMinCoolSetpoint: 18,
+		EquipProtocolMaxHeatSetpoint: 40,
+	}
+
+	caps := deviceInfo.Capabilities()
+	st.Expect(t, caps.Modes(), []daikin.Mode{daikin.ModeOff, daikin.ModeCool})
+	st.Expect(t, caps.SupportsMode(daikin.ModeAuto), false)
+	st.Expect(t, caps.CoolSetpointMin, daikin.Celsius(18))
+	st.Expect(t, caps.HeatSetpointMax, daikin.Celsius(32))
+}
+
+func TestCapabilitiesNonCommunicating(t *testing.T) {
+	deviceInfo := &daikin.DeviceInfo{
+		CtOutdoorUnitType: 255,
+		CtIFCUnitType:     255,
+		CtAHUnitType:      255,
+	}
+
+	caps := deviceInfo.Capabilities()
+	st.Expect(t, caps.Communicating, false)
+	st.Expect(t, caps.Modes(), []daikin.Mode{daikin.ModeOff, daikin.ModeHeat, daikin.ModeCool, daikin.ModeAuto})
+
+	deviceInfo.ModeEmHeatAvailable = true
+	st.Expect(t, deviceInfo.Capabilities().SupportsMode(daikin.ModeEmHeat), true)
+}
+
+func TestModeString(t *testing.T) {
+	st.Expect(t, daikin.ModeEmHeat.String(), "Emergency Heat")
+	st.Expect(t, fmt.Sprint(daikin.ModeCool), "Cool")
+}
+
+func TestCapabilitiesModeLimit(t *testing.T) {
+	deviceInfo := loadDeviceInfo(t)
+
+	deviceInfo.ModeLimit = int(daikin.ModeLimitHeatOnly)
+	st.Expect(t, deviceInfo.Capabilities().Modes(), []daikin.Mode{daikin.ModeOff, daikin.ModeHeat, daikin.ModeEmHeat})
+
+	deviceInfo.ModeLimit = int(daikin.ModeLimitCoolOnly)
+	st.Expect(t, deviceInfo.Capabilities().Modes(), []daikin.Mode{daikin.ModeOff, daikin.ModeCool})
+
+	deviceInfo.ModeLimit = int(daikin.ModeLimitNone)
+	st.Expect(t, deviceInfo.Capabilities().SupportsMode(daikin.ModeAuto), true)
+}
+
+func TestSetModeUnsupported(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Times(4).
+		Reply(200).
+		JSON(map[string]interface{}{"ctOutdoorUnitType": 6, "ctSystemCapHeat": true, "ctSystemCapCompressorHeat": true, "modeEmHeatAvailable": true})
+
+	d := daikin.New(email, password)
+
+	err := d.SetMode(deviceId, daikin.Mode(9))
+	st.Expect(t, err, errors.New("invalid mode"))
+
+	err = d.SetMode(deviceId, daikin.ModeEmHeat)
+	st.Expect(t, err, errors.New("emergency heat is not available on this device"))
+
+	err = d.SetMode(deviceId, daikin.ModeCool)
+	st.Expect(t, err, errors.New("mode is not supported by this system"))
+
+	err = d.Update(deviceId).Mode(daikin.ModeAuto).Apply(context.Background())
+	st.Expect(t, err, errors.New("mode is not supported by this system"))
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestSetLockoutSettingsUnsupported(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Times(2).
+		Reply(200).
+		JSON(map[string]interface{}{"ctIFCUnitType": 3, "ctSystemCapHeat": true, "ctSystemCapGasHeat": true})
+
+	d := daikin.New(email, password)
+
+	update := daikin.LockoutSettingsUpdate{EmHeatAvailable: boolPtr(true)}
+	err := d.SetLockoutSettings(deviceId, update)
+	st.Expect(t, err, errors.New("emergency heat is not available on this device"))
+
+	update = daikin.LockoutSettingsUpdate{HeatPumpLockoutEnabled: boolPtr(true)}
+	err = d.SetLockoutSettings(deviceId, update)
+	st.Expect(t, err, errors.New("heat pump lockout requires a heat pump"))
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestSetFanUnsupported(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Times(7).
+		Reply(200).
+		JSON(map[string]interface{}{"ctOutdoorUnitType": 6, "ctSystemCapCool": true})
+
+	d := daikin.New(email, password)
+
+	err := d.SetFanMode(deviceId, daikin.FanCirculateOn)
+	st.Expect(t, err, errors.New("indoor fan is not available on this device"))
+
+	err = d.SetFanSpeed(deviceId, daikin.FanCirculateSpeedLow)
+	st.Expect(t, err, errors.New("indoor fan is not available on this device"))
+
+	err = d.SetFanClean(deviceId, true)
+	st.Expect(t, err, errors.New("indoor fan is not available on this device"))
+
+	err = d.SetFanCirculationSchedule(deviceId, 8*time.Hour, 22*time.Hour, daikin.FanCirculateDuration15Min)
+	st.Expect(t, err, errors.New("indoor fan is not available on this device"))
+
+	err = d.SetFanExtend(deviceId, 90*time.Second, 0)
+	st.Expect(t, err, errors.New("indoor fan is not available on this device"))
+
+	err = d.SetOneCleanConfig(deviceId, daikin.OneCleanConfig{Duration: time.Hour})
+	st.Expect(t, err, errors.New("indoor fan is not available on this device"))
+
+	err = d.Update(deviceId).FanSpeed(daikin.FanCirculateSpeedLow).Apply(context.Background())
+	st.Expect(t, err, errors.New("indoor fan is not available on this device"))
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestSetFanExtendUnsupported(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		JSON(map[string]interface{}{"ctAHUnitType": 2, "ctSystemCapCool": true})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		JSON(map[string]interface{}{"ctAHUnitType": 2, "ctSystemCapHeat": true})
+
+	d := daikin.New(email, password)
+
+	err := d.SetFanExtend(deviceId, 0, 90*time.Second)
+	st.Expect(t, err, errors.New("heating is not available on this device"))
+
+	err = d.SetFanExtend(deviceId, 90*time.Second, 0)
+	st.Expect(t, err, errors.New("cooling is not available on this device"))
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestSetOneCleanConfigWithoutIAQSensor(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Times(3).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{
+			"oneCleanFanDuration":     1,
+			"oneCleanFanSpeed":        daikin.FanCirculateSpeedMed,
+			"oneCleanAction":          daikin.OneCleanActionRunFan,
+			"oneCleanAQITrigger":      500,
+			"oneCleanParticleTrigger": 500,
+			"oneCleanVOCtrigger":      60000,
+		}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	d := daikin.New(email, password)
+	deviceInfo, err := d.GetDeviceInfo(deviceId)
+	st.Expect(t, err, nil)
+
+	config := deviceInfo.OneCleanConfig()
+	config.Duration = time.Hour
+	config.ParticleTrigger = 150
+
+	err = d.SetOneCleanConfig(deviceId, config)
+	st.Expect(t, err, errors.New("air quality triggers require an IAQ sensor"))
+
+	// the device's own triggers can be written back
+	config.ParticleTrigger = 500
+	err = d.SetOneCleanConfig(deviceId, config)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestRunSystemTestUnsupported(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Times(3).
+		Reply(200).
+		JSON(map[string]interface{}{"ctOutdoorUnitType": 6, "ctSysTestHumidifier": 1, "ctSysTestDehumidifier": 1, "ctSysTestVentilator": 1})
+
+	d := daikin.New(email, password)
+	d.InstallerMode = true
+	ctx := context.Background()
+
+	for _, test := range []daikin.SystemTest{daikin.SystemTestHumidifier, daikin.SystemTestDehumidifier, daikin.SystemTestVentilator} {
+		_, err := d.RunSystemTest(ctx, deviceId, test, daikin.SystemTestOptions{})
+		st.Expect(t, err, errors.New("system test is not available on this device"))
+	}
+
+	st.Expect(t, gock.IsDone(), true)
+}
@@ -2,28 +2,63 @@ package daikin
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 )
 
 type Daikin struct {
 	Email          string
 	Password       string
+	Verify         *VerifyOptions
+	DryRun         bool
+	InstallerMode  bool
+	recorder       recorder
+	tokenMu        sync.Mutex
 	tokenCache     *Token
 	tokenExpiresAt time.Time
 	httpClient     *http.Client
 	urlBase        string
 }
 
+const (
+	fanScheduleStep = 15 * time.Minute
+	fanExtendMax    = 30 * time.Minute
+
+	oneCleanDurationMax        = 12 * time.Hour
+	oneCleanAQITriggerMax      = 500
+	oneCleanParticleTriggerMax = 500
+	oneCleanVOCTriggerMax      = 60000
+
+	deviceNameMaxLength = 32
+
+	tempOffsetMax = 3
+	humOffsetMax  = 10
+
+	// lockout temperatures are outdoor temperatures in celsius
+	lockoutTempMin = -30
+	lockoutTempMax = 30
+)
+
 type SetTempParams struct {
 	CoolSetpoint float32
 	HeatSetpoint float32
 }
 
+// SetTemperatureParams holds the setpoints to change. A nil setpoint keeps
+// the device's current value, adjusted to keep the minimum distance.
+type SetTemperatureParams struct {
+	CoolSetpoint *Temperature
+	HeatSetpoint *Temperature
+}
+
 func New(email string, password string) *Daikin {
 	d := Daikin{
 		Email:      email,
@@ -35,6 +70,8 @@ func New(email string, password string) *Daikin {
 }
 
 func (d *Daikin) getToken() (string, error) {
+	d.tokenMu.Lock()
+	defer d.tokenMu.Unlock()
 
 	if d.tokenCache != nil && time.Now().Before(d.tokenExpiresAt) {
 		return d.tokenCache.AccessToken, nil
@@ -111,7 +148,11 @@ func (d *Daikin) GetDevices() (*Devices, error) {
 }
 
 func (d *Daikin) GetDeviceInfo(deviceId string) (*DeviceInfo, error) {
-	r, err := http.NewRequest("GET", d.urlBase+"/deviceData/"+deviceId, nil)
+	return d.getDeviceInfo(context.Background(), deviceId)
+}
+
+func (d *Daikin) getDeviceInfo(ctx context.Context, deviceId string) (*DeviceInfo, error) {
+	r, err := http.NewRequestWithContext(ctx, "GET", d.urlBase+"/deviceData/"+deviceId, nil)
 	if err != nil {
 		return nil, errors.New("http.NewRequest failed")
 	}
@@ -146,6 +187,18 @@ func (d *Daikin) GetDeviceInfo(deviceId string) (*DeviceInfo, error) {
 }
 
 func (d *Daikin) SetMode(deviceId string, mode Mode) error {
+	// off is always available
+	if mode != ModeOff {
+		deviceInfo, err := d.GetDeviceInfo(deviceId)
+		if err != nil {
+			return errors.New("get device info failed")
+		}
+
+		if err := deviceInfo.Capabilities().checkMode(mode); err != nil {
+			return err
+		}
+	}
+
 	data := map[string]interface{}{"mode": mode}
 
 	json, err := json.Marshal(data)
@@ -157,6 +210,18 @@ func (d *Daikin) SetMode(deviceId string, mode Mode) error {
 }
 
 func (d *Daikin) SetFanMode(deviceId string, fan_mode FanCirculate) error {
+	// turning circulation off needs no fan
+	if fan_mode != FanCirculateOff {
+		deviceInfo, err := d.GetDeviceInfo(deviceId)
+		if err != nil {
+			return errors.New("get device info failed")
+		}
+
+		if err := deviceInfo.Capabilities().checkFan(); err != nil {
+			return err
+		}
+	}
+
 	data := map[string]interface{}{"fanCirculate": fan_mode}
 
 	json, err := json.Marshal(data)
@@ -168,6 +233,15 @@ func (d *Daikin) SetFanMode(deviceId string, fan_mode FanCirculate) error {
 }
 
 func (d *Daikin) SetFanSpeed(deviceId string, fan_speed FanCirculateSpeed) error {
+	deviceInfo, err := d.GetDeviceInfo(deviceId)
+	if err != nil {
+		return errors.New("get device info failed")
+	}
+
+	if err := deviceInfo.Capabilities().checkFan(); err != nil {
+		return err
+	}
+
 	data := map[string]interface{}{"fanCirculateSpeed": fan_speed}
 
 	json, err := json.Marshal(data)
@@ -178,7 +252,89 @@ func (d *Daikin) SetFanSpeed(deviceId string, fan_speed FanCirculateSpeed) error
 	return d.updateDevice(deviceId, json)
 }
 
+func (d *Daikin) SetFanCirculationSchedule(deviceId string, start time.Duration, stop time.Duration, duty_cycle FanCirculateDuration) error {
+	if start < 0 || start >= 24*time.Hour || stop < 0 || stop >= 24*time.Hour {
+		return errors.New("schedule times must be within a single day")
+	}
+
+	if start%fanScheduleStep != 0 || stop%fanScheduleStep != 0 {
+		return errors.New("schedule times must be in 15 minute increments")
+	}
+
+	if start == stop {
+		return errors.New("schedule start and stop can not be equal")
+	}
+
+	if duty_cycle > FanCirculateDuration45Min {
+		return errors.New("invalid fan circulate duration")
+	}
+
+	deviceInfo, err := d.GetDeviceInfo(deviceId)
+	if err != nil {
+		return errors.New("get device info failed")
+	}
+
+	if err := deviceInfo.Capabilities().checkFan(); err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{
+		"fanCirculate":         FanCirculateSched,
+		"fanCirculateStart":    int(start / fanScheduleStep),
+		"fanCirculateStop":     int(stop / fanScheduleStep),
+		"fanCirculateDuration": duty_cycle,
+	}
+
+	json, err := json.Marshal(data)
+	if err != nil {
+		return errors.New("json marshal failed")
+	}
+
+	return d.updateDevice(deviceId, json)
+}
+
+func (d *Daikin) SetFanExtend(deviceId string, cool time.Duration, heat time.Duration) error {
+	if cool < 0 || cool > fanExtendMax || heat < 0 || heat > fanExtendMax {
+		return errors.New("fan extend time outside of allowable range")
+	}
+
+	// turning both extensions off needs no fan
+	if cool != 0 || heat != 0 {
+		deviceInfo, err := d.GetDeviceInfo(deviceId)
+		if err != nil {
+			return errors.New("get device info failed")
+		}
+
+		if err := deviceInfo.Capabilities().checkFanExtend(cool != 0, heat != 0); err != nil {
+			return err
+		}
+	}
+
+	data := map[string]interface{}{
+		"fanExtendCool": cool.Milliseconds(),
+		"fanExtendHeat": heat.Milliseconds(),
+	}
+
+	json, err := json.Marshal(data)
+	if err != nil {
+		return errors.New("json marshal failed")
+	}
+
+	return d.updateDevice(deviceId, json)
+}
+
 func (d *Daikin) SetFanClean(deviceId string, fan_clean_active bool) error {
+	if fan_clean_active {
+		deviceInfo, err := d.GetDeviceInfo(deviceId)
+		if err != nil {
+			return errors.New("get device info failed")
+		}
+
+		if err := deviceInfo.Capabilities().checkFan(); err != nil {
+			return err
+		}
+	}
+
 	data := map[string]interface{}{"oneCleanFanActive": fan_clean_active}
 
 	json, err := json.Marshal(data)
@@ -189,15 +345,23 @@ func (d *Daikin) SetFanClean(deviceId string, fan_clean_active bool) error {
 	return d.updateDevice(deviceId, json)
 }
 
-func (d *Daikin) SetTemp(deviceId string, params SetTempParams) error {
+func (d *Daikin) SetOneCleanConfig(deviceId string, config OneCleanConfig) error {
+	if config.Duration < time.Hour || config.Duration > oneCleanDurationMax || config.Duration%time.Hour != 0 {
+		return errors.New("one clean duration must be a whole number of hours between 1 and 12")
+	}
 
-	if params.CoolSetpoint == params.HeatSetpoint {
-		return errors.New("invalid setpoints provided")
+	if config.Speed > FanCirculateSpeedHigh {
+		return errors.New("invalid fan circulate speed")
 	}
 
-	if params.CoolSetpoint != 0 && params.HeatSetpoint != 0 &&
-		params.CoolSetpoint < params.HeatSetpoint {
-		return errors.New("cool setpoint can not be lower than heat setpoint")
+	if config.Action > OneCleanActionNotify {
+		return errors.New("invalid one clean action")
+	}
+
+	if config.AQITrigger < 0 || config.AQITrigger > oneCleanAQITriggerMax ||
+		config.ParticleTrigger < 0 || config.ParticleTrigger > oneCleanParticleTriggerMax ||
+		config.VOCTrigger < 0 || config.VOCTrigger > oneCleanVOCTriggerMax {
+		return errors.New("one clean trigger outside of allowable range")
 	}
 
 	deviceInfo, err := d.GetDeviceInfo(deviceId)
@@ -205,29 +369,64 @@ func (d *Daikin) SetTemp(deviceId string, params SetTempParams) error {
 		return errors.New("get device info failed")
 	}
 
-	if params.CoolSetpoint == 0 {
-		// hsp provided, default csp
-		params.CoolSetpoint = deviceInfo.CspHome
+	caps := deviceInfo.Capabilities()
+	if err := caps.checkFan(); err != nil {
+		return err
+	}
 
-		if (params.CoolSetpoint - params.HeatSetpoint) < deviceInfo.TempDeltaMin {
-			// min delta not met, increase csp
-			params.CoolSetpoint = params.HeatSetpoint + deviceInfo.TempDeltaMin
-		}
+	// without a sensor the triggers can not fire, so only the values the
+	// device already has are accepted
+	current := deviceInfo.OneCleanConfig()
+	if !caps.IAQSensor && (config.AQITrigger != current.AQITrigger ||
+		config.ParticleTrigger != current.ParticleTrigger || config.VOCTrigger != current.VOCTrigger) {
+		return errors.New("air quality triggers require an IAQ sensor")
 	}
 
-	if params.HeatSetpoint == 0 {
-		// csp provided, default hsp
-		params.HeatSetpoint = deviceInfo.HspHome
+	data := map[string]interface{}{
+		"oneCleanFanDuration":     int(config.Duration / time.Hour),
+		"oneCleanFanSpeed":        config.Speed,
+		"oneCleanAction":          config.Action,
+		"oneCleanAQITrigger":      config.AQITrigger,
+		"oneCleanParticleTrigger": config.ParticleTrigger,
+		"oneCleanVOCtrigger":      config.VOCTrigger,
+	}
 
-		if (params.CoolSetpoint - params.HeatSetpoint) < deviceInfo.TempDeltaMin {
-			// min delta not met, lower hsp
-			params.HeatSetpoint = params.CoolSetpoint - deviceInfo.TempDeltaMin
-		}
+	json, err := json.Marshal(data)
+	if err != nil {
+		return errors.New("json marshal failed")
 	}
 
-	if params.CoolSetpoint < deviceInfo.TempSPMin || params.CoolSetpoint > deviceInfo.TempSPMax ||
-		params.HeatSetpoint < deviceInfo.TempSPMin || params.HeatSetpoint > deviceInfo.TempSPMax {
-		return errors.New("setpoint(s) outside of allowable range")
+	return d.updateDevice(deviceId, json)
+}
+
+// SetTemp takes setpoints in celsius, where 0 leaves a setpoint unchanged.
+// Use SetTemperature to set a setpoint of 0°C.
+func (d *Daikin) SetTemp(deviceId string, params SetTempParams) error {
+	var cool, heat *float32
+	if params.CoolSetpoint != 0 {
+		cool = &params.CoolSetpoint
+	}
+	if params.HeatSetpoint != 0 {
+		heat = &params.HeatSetpoint
+	}
+
+	return d.setSetpoints(deviceId, cool, heat)
+}
+
+func (d *Daikin) setSetpoints(deviceId string, cool *float32, heat *float32) error {
+
+	if err := checkSetpoints(cool, heat); err != nil {
+		return err
+	}
+
+	deviceInfo, err := d.GetDeviceInfo(deviceId)
+	if err != nil {
+		return errors.New("get device info failed")
+	}
+
+	params, err := deviceInfo.resolveSetpoints(cool, heat)
+	if err != nil {
+		return err
 	}
 
 	data := map[string]interface{}{
@@ -246,13 +445,190 @@ func (d *Daikin) SetTemp(deviceId string, params SetTempParams) error {
 	return d.updateDevice(deviceId, json)
 }
 
+func (d *Daikin) SetDeviceName(deviceId string, name string) error {
+	name = strings.TrimSpace(name)
+
+	if name == "" || utf8.RuneCountInString(name) > deviceNameMaxLength {
+		return errors.New("device name must be between 1 and 32 characters")
+	}
+
+	data := map[string]interface{}{
+		"deviceName":       DeviceNameCustom,
+		"deviceNameCustom": name,
+	}
+
+	for preset, text := range deviceNamePresets {
+		if strings.EqualFold(name, text) {
+			data = map[string]interface{}{"deviceName": preset}
+			break
+		}
+	}
+
+	json, err := json.Marshal(data)
+	if err != nil {
+		return errors.New("json marshal failed")
+	}
+
+	return d.updateDevice(deviceId, json)
+}
+
+// SetCalibration offsets the thermostat's own temperature and humidity
+// sensors. Every thermostat has both, so no capability is checked.
+func (d *Daikin) SetCalibration(deviceId string, tempOffset float32, humOffset int) error {
+	if tempOffset < -tempOffsetMax || tempOffset > tempOffsetMax {
+		return errors.New("temperature offset outside of allowable range")
+	}
+
+	if humOffset < -humOffsetMax || humOffset > humOffsetMax {
+		return errors.New("humidity offset outside of allowable range")
+	}
+
+	data := map[string]interface{}{
+		"tempOffset": tempOffset,
+		"humOffset":  humOffset,
+	}
+
+	json, err := json.Marshal(data)
+	if err != nil {
+		return errors.New("json marshal failed")
+	}
+
+	return d.updateDevice(deviceId, json)
+}
+
+func (d *Daikin) SetTemperature(deviceId string, params SetTemperatureParams) error {
+	var cool, heat *float32
+	if params.CoolSetpoint != nil {
+		v := params.CoolSetpoint.setpoint()
+		cool = &v
+	}
+	if params.HeatSetpoint != nil {
+		v := params.HeatSetpoint.setpoint()
+		heat = &v
+	}
+
+	return d.setSetpoints(deviceId, cool, heat)
+}
+
+// SetLockoutSettings writes the settings set in update and leaves the others
+// as they are on the device.
+func (d *Daikin) SetLockoutSettings(deviceId string, update LockoutSettingsUpdate) error {
+	for _, temp := range []*Temperature{update.AuxHeatLockoutTemp, update.HeatPumpLockoutTemp, update.AuxHeaterHeatPumpLockoutTemp} {
+		if temp != nil && (temp.setpoint() < lockoutTempMin || temp.setpoint() > lockoutTempMax) {
+			return errors.New("lockout temperature outside of allowable range")
+		}
+	}
+
+	data := map[string]interface{}{}
+
+	if update.AuxHeatLockoutEnabled != nil {
+		data["auxHeatLockoutEnable"] = boolToInt(*update.AuxHeatLockoutEnabled)
+	}
+	if update.AuxHeatLockoutTemp != nil {
+		data["auxHeatLockoutTemp"] = update.AuxHeatLockoutTemp.setpoint()
+	}
+	if update.HeatPumpLockoutEnabled != nil {
+		data["heatPumpLockoutEnable"] = boolToInt(*update.HeatPumpLockoutEnabled)
+	}
+	if update.HeatPumpLockoutTemp != nil {
+		data["heatPumpLockoutTemp"] = update.HeatPumpLockoutTemp.setpoint()
+	}
+	if update.AuxHeaterHeatPumpLockoutEnabled != nil {
+		data["AuxHeaterHeatPumpLockoutEnable"] = *update.AuxHeaterHeatPumpLockoutEnabled
+	}
+	if update.AuxHeaterHeatPumpLockoutTemp != nil {
+		data["AuxHeaterHeatPumpLockout"] = update.AuxHeaterHeatPumpLockoutTemp.setpoint()
+	}
+	if update.DualFuelFurnaceLockoutEnabled != nil {
+		data["ctDualFuelFurnaceLockoutEnable"] = *update.DualFuelFurnaceLockoutEnabled
+	}
+	if update.EmHeatAvailable != nil {
+		data["modeEmHeatAvailable"] = *update.EmHeatAvailable
+	}
+
+	if len(data) == 0 {
+		return errors.New("no changes to apply")
+	}
+
+	deviceInfo, err := d.GetDeviceInfo(deviceId)
+	if err != nil {
+		return errors.New("get device info failed")
+	}
+
+	// check the aux/heat pump order against the values the device ends up with
+	if update.AuxHeatLockoutEnabled != nil || update.AuxHeatLockoutTemp != nil ||
+		update.HeatPumpLockoutEnabled != nil || update.HeatPumpLockoutTemp != nil {
+		auxEnabled := deviceInfo.AuxHeatLockoutEnable != 0
+		auxTemp := Celsius(deviceInfo.AuxHeatLockoutTemp)
+		hpEnabled := deviceInfo.HeatPumpLockoutEnable != 0
+		hpTemp := Celsius(deviceInfo.HeatPumpLockoutTemp)
+
+		if update.AuxHeatLockoutEnabled != nil {
+			auxEnabled = *update.AuxHeatLockoutEnabled
+		}
+		if update.AuxHeatLockoutTemp != nil {
+			auxTemp = Celsius(update.AuxHeatLockoutTemp.setpoint())
+		}
+		if update.HeatPumpLockoutEnabled != nil {
+			hpEnabled = *update.HeatPumpLockoutEnabled
+		}
+		if update.HeatPumpLockoutTemp != nil {
+			hpTemp = Celsius(update.HeatPumpLockoutTemp.setpoint())
+		}
+
+		if auxEnabled && hpEnabled && auxTemp <= hpTemp {
+			return errors.New("aux heat lockout temp must be above heat pump lockout temp")
+		}
+	}
+
+	if err := deviceInfo.Capabilities().checkLockoutSettings(update); err != nil {
+		return err
+	}
+
+	json, err := json.Marshal(data)
+	if err != nil {
+		return errors.New("json marshal failed")
+	}
+
+	return d.updateDevice(deviceId, json)
+}
+
 func (d *Daikin) UpdateDeviceRaw(deviceId string, json string) error {
 	return d.updateDevice(deviceId, []byte(json))
 }
 
 func (d *Daikin) updateDevice(deviceId string, body []byte) error {
+	return d.updateDeviceContext(context.Background(), deviceId, body)
+}
+
+func (d *Daikin) updateDeviceContext(ctx context.Context, deviceId string, body []byte) error {
+	if err := d.putDeviceData(ctx, deviceId, body); err != nil {
+		return err
+	}
+
+	if d.Verify == nil || d.DryRun {
+		return nil
+	}
+
+	result, err := d.verifyUpdate(ctx, deviceId, body, *d.Verify)
+	if err != nil {
+		return err
+	}
 
-	r, err := http.NewRequest("PUT", d.urlBase+"/deviceData/"+deviceId, bytes.NewBuffer(body))
+	if !result.Ok() {
+		return &VerifyError{Result: result}
+	}
+
+	return nil
+}
+
+func (d *Daikin) putDeviceData(ctx context.Context, deviceId string, body []byte) error {
+	if d.DryRun {
+		d.recorder.record(deviceId, body)
+		return nil
+	}
+
+	r, err := http.NewRequestWithContext(ctx, "PUT", d.urlBase+"/deviceData/"+deviceId, bytes.NewBuffer(body))
 	if err != nil {
 		return errors.New("http.NewRequest failed")
 	}
@@ -279,3 +655,128 @@ func (d *Daikin) updateDevice(deviceId string, body []byte) error {
 
 	return nil
 }
+
+func (di *DeviceInfo) DisplayName() string {
+	if di.DeviceName > 0 && di.DeviceName <= int(DeviceNameOffice) {
+		return DeviceNamePreset(di.DeviceName).String()
+	}
+	return di.DeviceNameCustom
+}
+
+func (di *DeviceInfo) Calibration() Calibration {
+	return Calibration{
+		TempOffset:        di.TempOffset,
+		HumOffset:         di.HumOffset,
+		RawTemperature:    di.SensorRawTemperature,
+		RawHumidity:       di.SensorRawHumidity,
+		DynamicTempOffset: di.SensorDynamicAlgorithmTempOffset,
+		DynamicHumOffset:  di.SensorDynamicAlgorithmHumOffset,
+	}
+}
+
+func (di *DeviceInfo) Location() *time.Location {
+	loc, err := time.LoadLocation(di.TimeZone)
+	if err != nil || di.TimeZone == "" {
+		return time.UTC
+	}
+	return loc
+}
+
+func (di *DeviceInfo) TemperatureUnit() TemperatureUnit {
+	return TemperatureUnit(di.Units)
+}
+
+func (di *DeviceInfo) LockoutSettings() LockoutSettings {
+	return LockoutSettings{
+		Units:                           di.TemperatureUnit(),
+		AuxHeatLockoutEnabled:           di.AuxHeatLockoutEnable != 0,
+		AuxHeatLockoutTemp:              Celsius(di.AuxHeatLockoutTemp),
+		HeatPumpLockoutEnabled:          di.HeatPumpLockoutEnable != 0,
+		HeatPumpLockoutTemp:             Celsius(di.HeatPumpLockoutTemp),
+		AuxHeaterHeatPumpLockoutEnabled: di.AuxHeaterHeatPumpLockoutEnable,
+		AuxHeaterHeatPumpLockoutTemp:    Celsius(di.AuxHeaterHeatPumpLockout),
+		AuxHeaterHeatPumpLockoutActive:  di.AuxHeaterHeatPumpLockoutStatus,
+		DualFuelFurnaceLockoutEnabled:   di.CtDualFuelFurnaceLockoutEnable,
+		EmHeatAvailable:                 di.ModeEmHeatAvailable,
+	}
+}
+
+func (di *DeviceInfo) FanSettings() FanSettings {
+	return FanSettings{
+		Mode:          di.FanCirculate,
+		Speed:         di.FanCirculateSpeed,
+		Active:        di.FanCirculateActive,
+		ScheduleStart: time.Duration(di.FanCirculateStart) * fanScheduleStep,
+		ScheduleStop:  time.Duration(di.FanCirculateStop) * fanScheduleStep,
+		DutyCycle:     FanCirculateDuration(di.FanCirculateDuration),
+		ExtendCool:    time.Duration(di.FanExtendCool) * time.Millisecond,
+		ExtendHeat:    time.Duration(di.FanExtendHeat) * time.Millisecond,
+	}
+}
+
+func (di *DeviceInfo) OneCleanConfig() OneCleanConfig {
+	return OneCleanConfig{
+		Duration:        time.Duration(di.OneCleanFanDuration) * time.Hour,
+		Speed:           FanCirculateSpeed(di.OneCleanFanSpeed),
+		Action:          OneCleanAction(di.OneCleanAction),
+		AQITrigger:      di.OneCleanAQITrigger,
+		ParticleTrigger: di.OneCleanParticleTrigger,
+		VOCTrigger:      di.OneCleanVOCtrigger,
+	}
+}
+
+func checkSetpoints(cool *float32, heat *float32) error {
+	if cool == nil && heat == nil || cool != nil && heat != nil && *cool == *heat {
+		return errors.New("invalid setpoints provided")
+	}
+
+	if cool != nil && heat != nil && *cool < *heat {
+		return errors.New("cool setpoint can not be lower than heat setpoint")
+	}
+
+	return nil
+}
+
+// resolveSetpoints fills in the setpoint that was not given from the device.
+func (di *DeviceInfo) resolveSetpoints(cool *float32, heat *float32) (SetTempParams, error) {
+	var params SetTempParams
+
+	switch {
+	case cool != nil && heat != nil:
+		params.CoolSetpoint = *cool
+		params.HeatSetpoint = *heat
+	case heat != nil:
+		// hsp provided, default csp
+		params.HeatSetpoint = *heat
+		params.CoolSetpoint = di.CspHome
+
+		if (params.CoolSetpoint - params.HeatSetpoint) < di.TempDeltaMin {
+			// min delta not met, increase csp
+			params.CoolSetpoint = params.HeatSetpoint + di.TempDeltaMin
+		}
+	default:
+		// csp provided, default hsp
+		params.CoolSetpoint = *cool
+		params.HeatSetpoint = di.HspHome
+
+		if (params.CoolSetpoint - params.HeatSetpoint) < di.TempDeltaMin {
+			// min delta not met, lower hsp
+			params.HeatSetpoint = params.CoolSetpoint - di.TempDeltaMin
+		}
+	}
+
+	caps := di.Capabilities()
+	if params.CoolSetpoint < caps.CoolSetpointMin.Celsius() || params.CoolSetpoint > caps.CoolSetpointMax.Celsius() ||
+		params.HeatSetpoint < caps.HeatSetpointMin.Celsius() || params.HeatSetpoint > caps.HeatSetpointMax.Celsius() {
+		return params, errors.New("setpoint(s) outside of allowable range")
+	}
+
+	return params, nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
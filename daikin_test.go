@@ -4,7 +4,9 @@ import (
 	"errors"
 	"path"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/h2non/gock"
 	"github.com/nbio/st"
@@ -330,6 +332,12 @@ func TestSetFanMode(t *testing.T) {
 		Reply(200).
 		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
 
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
 	gock.New(urlBase).
 		Put("/deviceData/"+deviceId).
 		MatchHeader("Authorization", "Bearer "+accessToken).
@@ -359,6 +367,12 @@ func TestSetFanSpeed(t *testing.T) {
 		Reply(200).
 		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
 
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
 	gock.New(urlBase).
 		Put("/deviceData/"+deviceId).
 		MatchHeader("Authorization", "Bearer "+accessToken).
@@ -389,6 +403,12 @@ func TestSetFanClean(t *testing.T) {
 		Reply(200).
 		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
 
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
 	gock.New(urlBase).
 		Put("/deviceData/"+deviceId).
 		MatchHeader("Authorization", "Bearer "+accessToken).
@@ -402,3 +422,566 @@ func TestSetFanClean(t *testing.T) {
 	st.Expect(t, err, nil)
 	st.Expect(t, gock.IsDone(), true)
 }
+
+func TestLockoutSettings(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	d := daikin.New(email, password)
+	deviceInfo, err := d.GetDeviceInfo(deviceId)
+	st.Expect(t, err, nil)
+
+	settings := deviceInfo.LockoutSettings()
+
+	st.Expect(t, settings.Units, daikin.TemperatureUnitCelsius)
+	st.Expect(t, settings.AuxHeatLockoutEnabled, true)
+	st.Expect(t, settings.AuxHeatLockoutTemp, daikin.Celsius(-20.5))
+	st.Expect(t, settings.HeatPumpLockoutEnabled, true)
+	st.Expect(t, settings.HeatPumpLockoutTemp, daikin.Celsius(-26.5))
+	st.Expect(t, settings.DualFuelFurnaceLockoutEnabled, true)
+	st.Expect(t, settings.EmHeatAvailable, true)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestSetLockoutSettingsInvalid(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Times(2).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	d := daikin.New(email, password)
+
+	err := d.SetLockoutSettings(deviceId, daikin.LockoutSettingsUpdate{})
+	st.Expect(t, err, errors.New("no changes to apply"))
+
+	err = d.SetLockoutSettings(deviceId, daikin.LockoutSettingsUpdate{AuxHeatLockoutTemp: tempPtr(daikin.Fahrenheit(100))})
+	st.Expect(t, err, errors.New("lockout temperature outside of allowable range"))
+
+	err = d.SetLockoutSettings(deviceId, daikin.LockoutSettingsUpdate{HeatPumpLockoutTemp: tempPtr(daikin.Celsius(-40))})
+	st.Expect(t, err, errors.New("lockout temperature outside of allowable range"))
+
+	update := daikin.LockoutSettingsUpdate{
+		AuxHeatLockoutTemp:  tempPtr(daikin.Celsius(-10)),
+		HeatPumpLockoutTemp: tempPtr(daikin.Celsius(-5)),
+	}
+	err = d.SetLockoutSettings(deviceId, update)
+	st.Expect(t, err, errors.New("aux heat lockout temp must be above heat pump lockout temp"))
+
+	// the device's aux heat lockout is at -20.5 °C
+	update = daikin.LockoutSettingsUpdate{HeatPumpLockoutTemp: tempPtr(daikin.Celsius(-15))}
+	err = d.SetLockoutSettings(deviceId, update)
+	st.Expect(t, err, errors.New("aux heat lockout temp must be above heat pump lockout temp"))
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestSetLockoutSettingsFahrenheit(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{
+			"auxHeatLockoutEnable":           1,
+			"auxHeatLockoutTemp":             4.5,
+			"heatPumpLockoutTemp":            -6.5,
+			"ctDualFuelFurnaceLockoutEnable": true,
+		}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	d := daikin.New(email, password)
+	update := daikin.LockoutSettingsUpdate{
+		AuxHeatLockoutEnabled:         boolPtr(true),
+		AuxHeatLockoutTemp:            tempPtr(daikin.Fahrenheit(40)),
+		HeatPumpLockoutTemp:           tempPtr(daikin.Fahrenheit(20)),
+		DualFuelFurnaceLockoutEnabled: boolPtr(true),
+	}
+	err := d.SetLockoutSettings(deviceId, update)
+
+	st.Expect(t, err, nil)
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func tempPtr(t daikin.Temperature) *daikin.Temperature {
+	return &t
+}
+
+func TestFanSettings(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	d := daikin.New(email, password)
+	deviceInfo, err := d.GetDeviceInfo(deviceId)
+	st.Expect(t, err, nil)
+
+	settings := deviceInfo.FanSettings()
+
+	st.Expect(t, settings.Mode, daikin.FanCirculateOff)
+	st.Expect(t, settings.ScheduleStart, 12*time.Hour)
+	st.Expect(t, settings.ScheduleStop, 18*time.Hour)
+	st.Expect(t, settings.DutyCycle, daikin.FanCirculateDuration5Min)
+	st.Expect(t, settings.ExtendCool, 5*time.Minute)
+	st.Expect(t, settings.ExtendHeat, 5*time.Minute)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestSetFanCirculationSchedule(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{"fanCirculate": daikin.FanCirculateSched, "fanCirculateStart": 34, "fanCirculateStop": 88, "fanCirculateDuration": daikin.FanCirculateDuration15Min}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	d := daikin.New(email, password)
+	err := d.SetFanCirculationSchedule(deviceId, 8*time.Hour+30*time.Minute, 22*time.Hour, daikin.FanCirculateDuration15Min)
+
+	st.Expect(t, err, nil)
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestSetFanCirculationScheduleInvalid(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	d := daikin.New(email, password)
+
+	err := d.SetFanCirculationSchedule(deviceId, 8*time.Hour+10*time.Minute, 22*time.Hour, daikin.FanCirculateDuration15Min)
+	st.Expect(t, err, errors.New("schedule times must be in 15 minute increments"))
+
+	err = d.SetFanCirculationSchedule(deviceId, 8*time.Hour, 25*time.Hour, daikin.FanCirculateDuration15Min)
+	st.Expect(t, err, errors.New("schedule times must be within a single day"))
+
+	err = d.SetFanCirculationSchedule(deviceId, 8*time.Hour, 8*time.Hour, daikin.FanCirculateDuration15Min)
+	st.Expect(t, err, errors.New("schedule start and stop can not be equal"))
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestSetFanExtend(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{"fanExtendCool": 90000, "fanExtendHeat": 0}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	d := daikin.New(email, password)
+
+	err := d.SetFanExtend(deviceId, time.Hour, 0)
+	st.Expect(t, err, errors.New("fan extend time outside of allowable range"))
+
+	err = d.SetFanExtend(deviceId, 90*time.Second, 0)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestSetOneCleanConfig(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		JSON(map[string]interface{}{"ctIFCUnitType": 3, "ctIAQsensorExists": true})
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{
+			"oneCleanFanDuration":     3,
+			"oneCleanFanSpeed":        daikin.FanCirculateSpeedMed,
+			"oneCleanAction":          daikin.OneCleanActionRunFan,
+			"oneCleanAQITrigger":      500,
+			"oneCleanParticleTrigger": 150,
+			"oneCleanVOCtrigger":      60000,
+		}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	d := daikin.New(email, password)
+	deviceInfo, err := d.GetDeviceInfo(deviceId)
+	st.Expect(t, err, nil)
+
+	config := deviceInfo.OneCleanConfig()
+	st.Expect(t, config.Duration, 3*time.Hour)
+	st.Expect(t, config.Speed, daikin.FanCirculateSpeedMed)
+	st.Expect(t, config.Action, daikin.OneCleanActionRunFan)
+	st.Expect(t, config.ParticleTrigger, 500)
+
+	config.ParticleTrigger = 150
+	err = d.SetOneCleanConfig(deviceId, config)
+
+	st.Expect(t, err, nil)
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestSetOneCleanConfigInvalid(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	d := daikin.New(email, password)
+
+	err := d.SetOneCleanConfig(deviceId, daikin.OneCleanConfig{Duration: 90 * time.Minute})
+	st.Expect(t, err, errors.New("one clean duration must be a whole number of hours between 1 and 12"))
+
+	err = d.SetOneCleanConfig(deviceId, daikin.OneCleanConfig{Duration: time.Hour, Action: 2})
+	st.Expect(t, err, errors.New("invalid one clean action"))
+
+	err = d.SetOneCleanConfig(deviceId, daikin.OneCleanConfig{Duration: time.Hour, VOCTrigger: -1})
+	st.Expect(t, err, errors.New("one clean trigger outside of allowable range"))
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestDisplayName(t *testing.T) {
+	deviceInfo := daikin.DeviceInfo{DeviceName: int(daikin.DeviceNameMainRoom), DeviceNameCustom: "Other"}
+	st.Expect(t, deviceInfo.DisplayName(), "Main Room")
+
+	deviceInfo = daikin.DeviceInfo{DeviceName: int(daikin.DeviceNameCustom), DeviceNameCustom: "Workshop"}
+	st.Expect(t, deviceInfo.DisplayName(), "Workshop")
+
+	// out of range values are not truncated onto a preset
+	deviceInfo = daikin.DeviceInfo{DeviceName: 257, DeviceNameCustom: "Workshop"}
+	st.Expect(t, deviceInfo.DisplayName(), "Workshop")
+}
+
+func TestSetDeviceName(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{"deviceName": daikin.DeviceNameKitchen}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{"deviceName": daikin.DeviceNameCustom, "deviceNameCustom": "Workshop"}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{"deviceName": daikin.DeviceNameCustom, "deviceNameCustom": strings.Repeat("é", 32)}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	d := daikin.New(email, password)
+
+	err := d.SetDeviceName(deviceId, "kitchen")
+	st.Expect(t, err, nil)
+
+	err = d.SetDeviceName(deviceId, "Workshop")
+	st.Expect(t, err, nil)
+
+	err = d.SetDeviceName(deviceId, " ")
+	st.Expect(t, err, errors.New("device name must be between 1 and 32 characters"))
+
+	// the limit is in characters, not bytes
+	err = d.SetDeviceName(deviceId, strings.Repeat("é", 32))
+	st.Expect(t, err, nil)
+
+	err = d.SetDeviceName(deviceId, strings.Repeat("é", 33))
+	st.Expect(t, err, errors.New("device name must be between 1 and 32 characters"))
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestSetCalibration(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{"tempOffset": -1.5, "humOffset": 4}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	d := daikin.New(email, password)
+	deviceInfo, err := d.GetDeviceInfo(deviceId)
+	st.Expect(t, err, nil)
+
+	calibration := deviceInfo.Calibration()
+	st.Expect(t, calibration.TempOffset, float32(0))
+	st.Expect(t, calibration.RawTemperature, float32(28.5))
+	st.Expect(t, calibration.RawHumidity, 44)
+	st.Expect(t, calibration.DynamicTempOffset, float32(-6.3))
+
+	err = d.SetCalibration(deviceId, 5, 0)
+	st.Expect(t, err, errors.New("temperature offset outside of allowable range"))
+
+	err = d.SetCalibration(deviceId, 0, -20)
+	st.Expect(t, err, errors.New("humidity offset outside of allowable range"))
+
+	err = d.SetCalibration(deviceId, -1.5, 4)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestSetTemperatureFahrenheit(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{"cspHome": 24, "hspHome": 20, "schedOverride": 1}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	d := daikin.New(email, password)
+	params := daikin.SetTemperatureParams{CoolSetpoint: tempPtr(daikin.Fahrenheit(75)), HeatSetpoint: tempPtr(daikin.Fahrenheit(68))}
+	err := d.SetTemperature(deviceId, params)
+
+	st.Expect(t, err, nil)
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestSetTemperatureRounding(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	d := daikin.New(email, password)
+	d.DryRun = true
+
+	params := daikin.SetTemperatureParams{CoolSetpoint: tempPtr(daikin.Celsius(22.3)), HeatSetpoint: tempPtr(daikin.Celsius(19.8))}
+	err := d.SetTemperature(deviceId, params)
+	st.Expect(t, err, nil)
+
+	writes := d.RecordedWrites()
+	st.Expect(t, len(writes), 1)
+	st.Expect(t, string(writes[0].Body), `{"cspHome":22.5,"hspHome":20,"schedOverride":1}`)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestSetTemperatureFreezing(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	d := daikin.New(email, password)
+
+	// 32°F is 0°C, which must not be mistaken for an unset setpoint
+	params := daikin.SetTemperatureParams{HeatSetpoint: tempPtr(daikin.Fahrenheit(32))}
+	err := d.SetTemperature(deviceId, params)
+	st.Expect(t, err, errors.New("setpoint(s) outside of allowable range"))
+
+	err = d.SetTemperature(deviceId, daikin.SetTemperatureParams{})
+	st.Expect(t, err, errors.New("invalid setpoints provided"))
+
+	st.Expect(t, gock.IsDone(), true)
+}
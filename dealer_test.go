@@ -0,0 +1,209 @@
+package daikin_test
+
+import (
+	"errors"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/nbio/st"
+	"github.com/redgoose/daikin-skyport"
+)
+
+func TestDealerAccessFixture(t *testing.T) {
+	deviceInfo := loadDeviceInfo(t)
+
+	st.Expect(t, deviceInfo.PendingDealerRequest() == nil, true)
+	st.Expect(t, deviceInfo.DealerAccessGrant() == nil, true)
+	st.Expect(t, deviceInfo.DealerContact(), daikin.DealerContact{})
+}
+
+func TestDealerAccess(t *testing.T) {
+	requested := time.Date(2024, 5, 6, 14, 30, 0, 0, time.UTC)
+
+	deviceInfo := &daikin.DeviceInfo{
+		DealerName:                   "Cool Air HVAC",
+		DealerPhone:                  "555-0100",
+		DealerAccessRequestID:        "req-2",
+		DealerAccessRequestName:      "Cool Air HVAC",
+		DealerAccessRequestPhone:     "555-0101",
+		DealerAccessRequestTimestamp: int(requested.Unix()),
+		DealerAccessRequestDuration:  120,
+		DealerAccessResponseID:       "req-1",
+		DealerAccess:                 1,
+		DealerAccessGrantID:          "req-1",
+		DealerAccessGrantName:        "Cool Air HVAC",
+		DealerAccessGrantExpires:     int(requested.Add(2 * time.Hour).Unix()),
+	}
+
+	contact := deviceInfo.DealerContact()
+	st.Expect(t, contact.Name, "Cool Air HVAC")
+	st.Expect(t, contact.Phone, "555-0100")
+
+	request := deviceInfo.PendingDealerRequest()
+	st.Expect(t, request.Id, "req-2")
+	st.Expect(t, request.Phone, "555-0101")
+	st.Expect(t, request.Time.Equal(requested), true)
+	st.Expect(t, request.Duration, 2*time.Hour)
+
+	grant := deviceInfo.DealerAccessGrantAt(requested)
+	st.Expect(t, grant.Id, "req-1")
+	st.Expect(t, grant.Expires.Equal(requested.Add(2*time.Hour)), true)
+
+	// an expired grant is no access, even while dealerAccess is still set
+	st.Expect(t, deviceInfo.DealerAccessGrantAt(requested.Add(2*time.Hour)) == nil, true)
+	st.Expect(t, deviceInfo.DealerAccessGrant() == nil, true)
+
+	deviceInfo.DealerAccessGrantExpires = 0
+	st.Expect(t, deviceInfo.DealerAccessGrant().Id, "req-1")
+
+	deviceInfo.DealerAccessResponseID = "req-2"
+	st.Expect(t, deviceInfo.PendingDealerRequest() == nil, true)
+}
+
+func TestGrantDealerAccess(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Times(2).
+		Reply(200).
+		JSON(map[string]interface{}{"dealerAccessRequestId": "req-2", "dealerAccessRequestDuration": 120})
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{"dealerAccessResponseId": "req-2", "dealerAccessResponseStatus": daikin.DealerAccessGranted, "dealerAccessRequestDuration": 120}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{"dealerAccessResponseId": "req-2", "dealerAccessResponseStatus": daikin.DealerAccessGranted, "dealerAccessRequestDuration": 30}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	d := daikin.New(email, password)
+
+	err := d.GrantDealerAccess(deviceId, -time.Minute)
+	st.Expect(t, err, errors.New("dealer access duration must not be negative"))
+
+	err = d.GrantDealerAccess(deviceId, 0)
+	st.Expect(t, err, nil)
+
+	err = d.GrantDealerAccess(deviceId, 30*time.Minute)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestDenyDealerAccess(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		JSON(map[string]interface{}{"dealerAccessRequestId": "req-2"})
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{"dealerAccessResponseId": "req-2", "dealerAccessResponseStatus": daikin.DealerAccessDenied}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	d := daikin.New(email, password)
+
+	err := d.DenyDealerAccess(deviceId)
+	st.Expect(t, err, errors.New("no pending dealer access request"))
+
+	err = d.DenyDealerAccess(deviceId)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestRevokeDealerAccess(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		JSON(map[string]interface{}{"dealerAccess": 1, "dealerAccessGrantId": "req-1", "dealerAccessGrantExpires": 1714998600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		JSON(map[string]interface{}{"dealerAccess": 1, "dealerAccessGrantId": "req-1"})
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{"dealerAccess": 0, "dealerAccessResponseId": "req-1", "dealerAccessResponseStatus": daikin.DealerAccessRevoked}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	d := daikin.New(email, password)
+
+	err := d.RevokeDealerAccess(deviceId)
+	st.Expect(t, err, errors.New("no dealer has access"))
+
+	err = d.RevokeDealerAccess(deviceId)
+	st.Expect(t, err, errors.New("no dealer has access"))
+
+	err = d.RevokeDealerAccess(deviceId)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
@@ -0,0 +1,147 @@
+package daikin
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// sent when the utility has not published a price
+const adrPriceNotAvailable = 0xFFFF
+
+type DemandResponseEvent struct {
+	Id    int
+	Start time.Time
+	Stop  time.Time
+}
+
+type DemandResponse struct {
+	Enabled             bool
+	State               DemandResponseState
+	Status              DemandResponseStatus
+	Action              DemandResponseAction
+	Event               *DemandResponseEvent
+	OffsetCool          TemperatureDelta
+	OffsetHeat          TemperatureDelta
+	StaticCool          Temperature
+	StaticHeat          Temperature
+	CoolMax             Temperature
+	HeatMin             Temperature
+	Price               *int
+	PriceTrigger        int
+	PriceTriggerEnabled bool
+}
+
+// DemandResponse returns the demand response enrollment and the current
+// event, if any. Event times are zero until the event has started or stopped.
+func (di *DeviceInfo) DemandResponse() DemandResponse {
+	dr := DemandResponse{
+		Enabled:             di.AdrEnabled,
+		State:               DemandResponseState(di.AdrState),
+		Status:              DemandResponseStatus(di.AdrStatus),
+		Action:              DemandResponseAction(di.AdrAction),
+		OffsetCool:          TemperatureDelta(di.AdrOffsetCool),
+		OffsetHeat:          TemperatureDelta(di.AdrOffsetHeat),
+		StaticCool:          Celsius(float32(di.AdrStaticCool)),
+		StaticHeat:          Celsius(float32(di.AdrStaticHeat)),
+		CoolMax:             Celsius(float32(di.AdrEventCoolMax)),
+		HeatMin:             Celsius(float32(di.AdrEventHeatMin)),
+		PriceTrigger:        di.AdrPriceTrigger,
+		PriceTriggerEnabled: di.AdrPriceTriggerEnabled,
+	}
+
+	if di.AdrPrice != adrPriceNotAvailable {
+		price := di.AdrPrice
+		dr.Price = &price
+	}
+
+	if di.AdrEventID != 0 {
+		dr.Event = &DemandResponseEvent{
+			Id:    di.AdrEventID,
+			Start: unixTime(di.AdrActualStart),
+			Stop:  unixTime(di.AdrActualStop),
+		}
+	}
+
+	return dr
+}
+
+func unixTime(v int) time.Time {
+	if v == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(v), 0)
+}
+
+func (d *Daikin) SetDemandResponseEnabled(deviceId string, enabled bool) error {
+	data := map[string]interface{}{
+		"adrEnabled": enabled,
+	}
+
+	json, err := json.Marshal(data)
+	if err != nil {
+		return errors.New("json marshal failed")
+	}
+
+	return d.updateDevice(deviceId, json)
+}
+
+// SetPriceTrigger starts demand response when the utility price reaches
+// price, in the same units as DemandResponse.Price.
+func (d *Daikin) SetPriceTrigger(deviceId string, enabled bool, price int) error {
+	if price < 0 || price >= adrPriceNotAvailable {
+		return errors.New("price trigger outside of allowable range")
+	}
+
+	// the trigger is compared against the utility price, so it can only be
+	// turned on when the device receives one
+	if enabled {
+		deviceInfo, err := d.GetDeviceInfo(deviceId)
+		if err != nil {
+			return errors.New("get device info failed")
+		}
+
+		if !deviceInfo.Capabilities().UtilityPrice {
+			return errors.New("utility price is not available on this device")
+		}
+	}
+
+	data := map[string]interface{}{
+		"adrPriceTriggerEnabled": enabled,
+		"adrPriceTrigger":        price,
+	}
+
+	json, err := json.Marshal(data)
+	if err != nil {
+		return errors.New("json marshal failed")
+	}
+
+	return d.updateDevice(deviceId, json)
+}
+
+func (d *Daikin) OptOutCurrentEvent(deviceId string) error {
+	deviceInfo, err := d.GetDeviceInfo(deviceId)
+	if err != nil {
+		return errors.New("get device info failed")
+	}
+
+	dr := deviceInfo.DemandResponse()
+	if dr.Event == nil || dr.State == DemandResponseIdle {
+		return errors.New("no demand response event in progress")
+	}
+
+	if dr.Status == DemandResponseOptedOut {
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"adrStatus": DemandResponseOptedOut,
+	}
+
+	json, err := json.Marshal(data)
+	if err != nil {
+		return errors.New("json marshal failed")
+	}
+
+	return d.updateDevice(deviceId, json)
+}
@@ -0,0 +1,186 @@
+package daikin_test
+
+import (
+	"errors"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/nbio/st"
+	"github.com/redgoose/daikin-skyport"
+)
+
+func TestDemandResponseFixture(t *testing.T) {
+	deviceInfo := loadDeviceInfo(t)
+
+	dr := deviceInfo.DemandResponse()
+	st.Expect(t, dr.Enabled, true)
+	st.Expect(t, dr.State, daikin.DemandResponseIdle)
+	st.Expect(t, dr.Action, daikin.DemandResponseOffset)
+	st.Expect(t, dr.Event == nil, true)
+	st.Expect(t, dr.Price == nil, true)
+	st.Expect(t, dr.PriceTrigger, 10)
+	st.Expect(t, dr.OffsetCool, daikin.TemperatureDelta(2))
+	st.Expect(t, dr.OffsetHeat, daikin.TemperatureDelta(-2))
+	st.Expect(t, dr.StaticCool, daikin.Celsius(27))
+	st.Expect(t, dr.HeatMin, daikin.Celsius(18))
+}
+
+func TestDemandResponseEvent(t *testing.T) {
+	start := time.Date(2024, 7, 15, 21, 0, 0, 0, time.UTC)
+
+	deviceInfo := &daikin.DeviceInfo{
+		AdrState:       int(daikin.DemandResponseActive),
+		AdrStatus:      int(daikin.DemandResponseOptedIn),
+		AdrEventID:     1234,
+		AdrActualStart: int(start.Unix()),
+		AdrPrice:       42,
+	}
+
+	dr := deviceInfo.DemandResponse()
+	st.Expect(t, dr.State, daikin.DemandResponseActive)
+	st.Expect(t, dr.Event.Id, 1234)
+	st.Expect(t, dr.Event.Start.Equal(start), true)
+	st.Expect(t, dr.Event.Stop.IsZero(), true)
+	st.Expect(t, *dr.Price, 42)
+}
+
+func TestSetDemandResponseEnabled(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{"adrEnabled": false}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	d := daikin.New(email, password)
+
+	err := d.SetDemandResponseEnabled(deviceId, false)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestSetPriceTrigger(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		JSON(map[string]interface{}{"adrPrice": 12})
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{"adrPriceTriggerEnabled": true, "adrPriceTrigger": 25}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	d := daikin.New(email, password)
+
+	err := d.SetPriceTrigger(deviceId, true, -1)
+	st.Expect(t, err, errors.New("price trigger outside of allowable range"))
+
+	err = d.SetPriceTrigger(deviceId, true, 25)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestOptOutCurrentEvent(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		JSON(map[string]interface{}{"adrState": daikin.DemandResponseActive, "adrStatus": daikin.DemandResponseOptedIn, "adrEventId": 1234})
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{"adrStatus": daikin.DemandResponseOptedOut}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	d := daikin.New(email, password)
+
+	err := d.OptOutCurrentEvent(deviceId)
+	st.Expect(t, err, errors.New("no demand response event in progress"))
+
+	err = d.OptOutCurrentEvent(deviceId)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestSetPriceTriggerUnsupported(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	d := daikin.New(email, password)
+
+	err := d.SetPriceTrigger(deviceId, true, 25)
+	st.Expect(t, err, errors.New("utility price is not available on this device"))
+
+	st.Expect(t, gock.IsDone(), true)
+}
@@ -0,0 +1,165 @@
+// Package diagnostics builds a refrigerant circuit report from the sensor
+// readings of communicating Daikin equipment.
+package diagnostics
+
+import (
+	"fmt"
+
+	"github.com/redgoose/daikin-skyport"
+)
+
+const defaultRefrigerant = "R-410A"
+
+// thresholds in °F, for a system running in cooling
+const (
+	superHeatHigh   = 20
+	superHeatLow    = 5
+	subCoolHigh     = 18
+	subCoolLow      = 5
+	splitHigh       = 22
+	splitLow        = 14
+	suctionFreezing = 32
+	dischargeHigh   = 225
+)
+
+type WarningCode string
+
+const (
+	WarningLowCharge          WarningCode = "low_charge"
+	WarningOvercharge         WarningCode = "overcharge"
+	WarningRestrictedAirflow  WarningCode = "restricted_airflow"
+	WarningLowSplit           WarningCode = "low_split"
+	WarningCoilFreezing       WarningCode = "coil_freezing"
+	WarningHighDischarge      WarningCode = "high_discharge"
+	WarningAssumedRefrigerant WarningCode = "assumed_refrigerant"
+)
+
+type Warning struct {
+	Code    WarningCode
+	Message string
+}
+
+type Report struct {
+	Refrigerant          string
+	Cooling              bool
+	SuctionPressure      *int
+	SuctionSaturation    *daikin.Temperature
+	SuctionTemperature   *daikin.Temperature
+	LiquidTemperature    *daikin.Temperature
+	DischargeTemperature *daikin.Temperature
+	// SuperHeat is calculated at the outdoor unit from suction pressure and temperature.
+	SuperHeat *daikin.TemperatureDelta
+	// CoilSuperHeat and SubCool are reported by the indoor coil or air handler.
+	CoilSuperHeat        *daikin.TemperatureDelta
+	SubCool              *daikin.TemperatureDelta
+	SupplyAirTemperature *daikin.Temperature
+	ReturnAirTemperature *daikin.Temperature
+	TemperatureSplit     *daikin.TemperatureDelta
+	Warnings             []Warning
+}
+
+func Analyze(deviceInfo *daikin.DeviceInfo) *Report {
+	report := &Report{
+		Cooling: deviceInfo.EquipmentStatus == daikin.EquipmentStatusCool ||
+			deviceInfo.EquipmentStatus == daikin.EquipmentStatusOvercool,
+	}
+
+	airHandler := deviceInfo.AirHandler()
+	if airHandler != nil && airHandler.RefrigerantType != "" {
+		report.Refrigerant = airHandler.RefrigerantType
+	} else {
+		report.Refrigerant = defaultRefrigerant
+		report.warn(WarningAssumedRefrigerant, "refrigerant type not reported, assuming %s", defaultRefrigerant)
+	}
+
+	if outdoor := deviceInfo.Outdoor(); outdoor != nil {
+		report.SuctionPressure = outdoor.SuctionPressure
+		report.SuctionTemperature = outdoor.SuctionTemperature
+		report.LiquidTemperature = outdoor.LiquidTemperature
+		report.DischargeTemperature = outdoor.DischargeTemperature
+
+		if outdoor.SuctionPressure != nil {
+			if saturation, ok := SaturationTemperature(report.Refrigerant, float64(*outdoor.SuctionPressure)); ok {
+				report.SuctionSaturation = &saturation
+			}
+		}
+
+		report.SuperHeat = delta(report.SuctionTemperature, report.SuctionSaturation)
+	}
+
+	if coil := deviceInfo.Coil(); coil != nil {
+		report.CoilSuperHeat = coil.SuperHeat
+		report.SubCool = coil.SubCool
+	}
+
+	if airHandler != nil {
+		if report.CoilSuperHeat == nil {
+			report.CoilSuperHeat = airHandler.SuperHeat
+		}
+		if report.SubCool == nil {
+			report.SubCool = airHandler.SubCool
+		}
+	}
+
+	if furnace := deviceInfo.Furnace(); furnace != nil {
+		report.SupplyAirTemperature = furnace.SupplyAirTemperature
+		report.ReturnAirTemperature = furnace.ReturnAirTemperature
+		report.TemperatureSplit = delta(report.ReturnAirTemperature, report.SupplyAirTemperature)
+	}
+
+	if report.Cooling {
+		report.checkCooling()
+	}
+
+	return report
+}
+
+func (r *Report) checkCooling() {
+	superHeat := r.SuperHeat
+	if superHeat == nil {
+		superHeat = r.CoilSuperHeat
+	}
+
+	if superHeat != nil && r.SubCool != nil {
+		sh := superHeat.Fahrenheit()
+		sc := r.SubCool.Fahrenheit()
+
+		if sh > superHeatHigh && sc < subCoolLow {
+			r.warn(WarningLowCharge, "high superheat (%.1f°F) with low subcool (%.1f°F) suggests a low refrigerant charge", sh, sc)
+		}
+
+		if sh < superHeatLow && sc > subCoolHigh {
+			r.warn(WarningOvercharge, "low superheat (%.1f°F) with high subcool (%.1f°F) suggests an overcharge", sh, sc)
+		}
+	}
+
+	if r.TemperatureSplit != nil {
+		split := r.TemperatureSplit.Fahrenheit()
+
+		if split > splitHigh {
+			r.warn(WarningRestrictedAirflow, "temperature split of %.1f°F is high, check filter and blower for restricted airflow", split)
+		} else if split < splitLow {
+			r.warn(WarningLowSplit, "temperature split of %.1f°F is low", split)
+		}
+	}
+
+	if r.SuctionSaturation != nil && r.SuctionSaturation.Fahrenheit() < suctionFreezing {
+		r.warn(WarningCoilFreezing, "suction saturation of %.1f°F is below freezing, the evaporator may ice up from restricted airflow", r.SuctionSaturation.Fahrenheit())
+	}
+
+	if r.DischargeTemperature != nil && r.DischargeTemperature.Fahrenheit() > dischargeHigh {
+		r.warn(WarningHighDischarge, "discharge temperature of %.1f°F is high", r.DischargeTemperature.Fahrenheit())
+	}
+}
+
+func (r *Report) warn(code WarningCode, format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, Warning{Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+func delta(a *daikin.Temperature, b *daikin.Temperature) *daikin.TemperatureDelta {
+	if a == nil || b == nil {
+		return nil
+	}
+	d := daikin.TemperatureDelta(a.Celsius() - b.Celsius())
+	return &d
+}
@@ -0,0 +1,89 @@
+package diagnostics_test
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/nbio/st"
+	"github.com/redgoose/daikin-skyport"
+	"github.com/redgoose/daikin-skyport/diagnostics"
+)
+
+func round(value float32) float64 {
+	return math.Round(float64(value)*10) / 10
+}
+
+func codes(report *diagnostics.Report) []diagnostics.WarningCode {
+	var codes []diagnostics.WarningCode
+	for _, warning := range report.Warnings {
+		codes = append(codes, warning.Code)
+	}
+	return codes
+}
+
+func TestSaturationTemperature(t *testing.T) {
+	temp, ok := diagnostics.SaturationTemperature("R410A", 118)
+	st.Expect(t, ok, true)
+	st.Expect(t, round(temp.Fahrenheit()), 40.0)
+
+	temp, ok = diagnostics.SaturationTemperature("r-22", 76.25)
+	st.Expect(t, ok, true)
+	st.Expect(t, round(temp.Fahrenheit()), 45.0)
+
+	_, ok = diagnostics.SaturationTemperature("R-410A", 1000)
+	st.Expect(t, ok, false)
+
+	_, ok = diagnostics.SaturationTemperature("R-12", 50)
+	st.Expect(t, ok, false)
+}
+
+func TestAnalyzeFixture(t *testing.T) {
+	body, err := os.ReadFile(path.Join("..", "fixtures", "device_info.json"))
+	st.Assert(t, err, nil)
+
+	var deviceInfo daikin.DeviceInfo
+	st.Assert(t, json.Unmarshal(body, &deviceInfo), nil)
+
+	report := diagnostics.Analyze(&deviceInfo)
+
+	st.Expect(t, report.Refrigerant, "R-410A")
+	st.Expect(t, report.Cooling, true)
+	st.Expect(t, *report.SuctionPressure, 109)
+	st.Expect(t, round(report.SuctionSaturation.Fahrenheit()), 35.8)
+	st.Expect(t, round(report.SuperHeat.Fahrenheit()), 7.5)
+	st.Expect(t, round(report.SubCool.Fahrenheit()), 30.9)
+	st.Expect(t, report.TemperatureSplit == nil, true)
+	st.Expect(t, codes(report), []diagnostics.WarningCode{diagnostics.WarningAssumedRefrigerant})
+}
+
+func TestAnalyzeLowChargeAndAirflow(t *testing.T) {
+	deviceInfo := &daikin.DeviceInfo{
+		EquipmentStatus:               daikin.EquipmentStatusCool,
+		CtOutdoorUnitType:             6,
+		CtOutdoorSuctionPressure:      100,
+		CtOutdoorSuctionTemperature:   600,
+		CtOutdoorLiquidTemperature:    32767,
+		CtOutdoorDischargeTemperature: 2400,
+		CtCoilUnitType:                7,
+		CtEEVCoilSuperHeatValue:       32767,
+		CtEEVCoilSubCoolValue:         20,
+		CtIFCUnitType:                 3,
+		CtIFCReturnAirTemperature:     780,
+		CtIFCSupplyAirTemperature:     540,
+		CtAHUnitType:                  255,
+	}
+
+	report := diagnostics.Analyze(deviceInfo)
+
+	st.Expect(t, codes(report), []diagnostics.WarningCode{
+		diagnostics.WarningAssumedRefrigerant,
+		diagnostics.WarningLowCharge,
+		diagnostics.WarningRestrictedAirflow,
+		diagnostics.WarningCoilFreezing,
+		diagnostics.WarningHighDischarge,
+	})
+	st.Expect(t, round(report.TemperatureSplit.Fahrenheit()), 24.0)
+}
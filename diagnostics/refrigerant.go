@@ -0,0 +1,67 @@
+package diagnostics
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/redgoose/daikin-skyport"
+)
+
+type ptPoint struct {
+	psig       float64
+	fahrenheit float64
+}
+
+// saturation pressure/temperature charts, psig to °F
+var ptCharts = map[string][]ptPoint{
+	"R-410A": {
+		{25.6, -20}, {36.1, -10}, {48.6, 0}, {62.2, 10}, {78.3, 20}, {96.8, 30},
+		{118.0, 40}, {142.2, 50}, {169.6, 60}, {200.6, 70}, {235.3, 80},
+		{274.1, 90}, {317.4, 100}, {365.4, 110}, {418.4, 120}, {476.6, 130},
+	},
+	"R-32": {
+		{26.8, -20}, {38.0, -10}, {51.0, 0}, {65.3, 10}, {82.0, 20}, {101.5, 30},
+		{123.8, 40}, {149.2, 50}, {178.0, 60}, {210.4, 70}, {246.7, 80},
+		{287.2, 90}, {332.2, 100}, {382.0, 110}, {437.0, 120}, {497.4, 130},
+	},
+	"R-22": {
+		{10.1, -20}, {16.5, -10}, {24.0, 0}, {32.8, 10}, {43.0, 20}, {54.9, 30},
+		{68.5, 40}, {84.0, 50}, {101.6, 60}, {121.4, 70}, {143.6, 80},
+		{168.4, 90}, {195.9, 100}, {226.4, 110}, {259.9, 120}, {296.8, 130},
+	},
+}
+
+// Refrigerants lists the refrigerant types with a known saturation chart.
+func Refrigerants() []string {
+	names := make([]string, 0, len(ptCharts))
+	for name := range ptCharts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SaturationTemperature returns the saturation temperature of the refrigerant
+// at the given gauge pressure, or false if the refrigerant is unknown or the
+// pressure is outside of the chart.
+func SaturationTemperature(refrigerant string, psig float64) (daikin.Temperature, bool) {
+	chart, ok := ptCharts[normalizeRefrigerant(refrigerant)]
+	if !ok || psig < chart[0].psig || psig > chart[len(chart)-1].psig {
+		return 0, false
+	}
+
+	i := sort.Search(len(chart), func(i int) bool { return chart[i].psig >= psig })
+	if chart[i].psig == psig {
+		return daikin.Fahrenheit(float32(chart[i].fahrenheit)), true
+	}
+
+	lo, hi := chart[i-1], chart[i]
+	f := lo.fahrenheit + (psig-lo.psig)/(hi.psig-lo.psig)*(hi.fahrenheit-lo.fahrenheit)
+	return daikin.Fahrenheit(float32(f)), true
+}
+
+func normalizeRefrigerant(refrigerant string) string {
+	name := strings.ToUpper(strings.TrimSpace(refrigerant))
+	name = strings.TrimPrefix(strings.TrimPrefix(name, "R-"), "R")
+	return "R-" + name
+}
@@ -0,0 +1,81 @@
+package daikin
+
+import (
+	"reflect"
+	"strings"
+)
+
+type FieldChange struct {
+	Field string
+	Old   interface{}
+	New   interface{}
+}
+
+type DiffOptions struct {
+	// IgnoreFields are JSON field names to leave out of the diff.
+	IgnoreFields []string
+	// IgnoreTimestamps skips date and timestamp fields.
+	IgnoreTimestamps bool
+	// IgnoreAirQualityHistory skips the hourly and daily indoor AQ rollups.
+	IgnoreAirQualityHistory bool
+	// IgnoreSignalStrength skips RF network and sensor signal readings.
+	IgnoreSignalStrength bool
+}
+
+func Diff(before *DeviceInfo, after *DeviceInfo) []FieldChange {
+	return DiffWithOptions(before, after, DiffOptions{})
+}
+
+func DiffWithOptions(before *DeviceInfo, after *DeviceInfo, opts DiffOptions) []FieldChange {
+	if before == nil {
+		before = &DeviceInfo{}
+	}
+
+	if after == nil {
+		after = &DeviceInfo{}
+	}
+
+	ignored := map[string]bool{}
+	for _, name := range opts.IgnoreFields {
+		ignored[name] = true
+	}
+
+	oldValue := reflect.ValueOf(before).Elem()
+	newValue := reflect.ValueOf(after).Elem()
+	fields := oldValue.Type()
+
+	var changes []FieldChange
+	for i := 0; i < fields.NumField(); i++ {
+		name := jsonName(fields.Field(i))
+		if name == "" || ignored[name] || opts.ignores(name) {
+			continue
+		}
+
+		o := oldValue.Field(i).Interface()
+		n := newValue.Field(i).Interface()
+		if !reflect.DeepEqual(o, n) {
+			changes = append(changes, FieldChange{Field: name, Old: o, New: n})
+		}
+	}
+
+	return changes
+}
+
+func (opts DiffOptions) ignores(name string) bool {
+	lower := strings.ToLower(name)
+
+	if opts.IgnoreTimestamps && (strings.HasSuffix(lower, "date") || strings.HasSuffix(lower, "timestamp")) {
+		return true
+	}
+
+	if opts.IgnoreAirQualityHistory && strings.HasPrefix(lower, "aqindoor") &&
+		(strings.Contains(lower, "valuehour") || strings.Contains(lower, "valueday")) {
+		return true
+	}
+
+	if opts.IgnoreSignalStrength && strings.HasSuffix(lower, "signal") {
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,47 @@
+package daikin_test
+
+import (
+	"testing"
+
+	"github.com/nbio/st"
+	"github.com/redgoose/daikin-skyport"
+)
+
+func TestDiff(t *testing.T) {
+	before := &daikin.DeviceInfo{Mode: daikin.ModeCool, CspHome: 22, HspHome: 17.5}
+	after := &daikin.DeviceInfo{Mode: daikin.ModeHeat, CspHome: 22, HspHome: 20}
+
+	changes := daikin.Diff(before, after)
+
+	st.Expect(t, len(changes), 2)
+	st.Expect(t, changes[0], daikin.FieldChange{Field: "hspHome", Old: float32(17.5), New: float32(20)})
+	st.Expect(t, changes[1], daikin.FieldChange{Field: "mode", Old: daikin.ModeCool, New: daikin.ModeHeat})
+}
+
+func TestDiffNoChanges(t *testing.T) {
+	deviceInfo := &daikin.DeviceInfo{Mode: daikin.ModeCool}
+
+	st.Expect(t, len(daikin.Diff(deviceInfo, deviceInfo)), 0)
+}
+
+func TestDiffIgnoresNoisyFields(t *testing.T) {
+	before := &daikin.DeviceInfo{Mode: daikin.ModeCool}
+	after := &daikin.DeviceInfo{
+		Mode:                           daikin.ModeCool,
+		IAQIndoorTimeStamp:             1700000000,
+		AqIndoorParticlesValueHour1Avg: 12,
+		RfNetworkSignal:                -60,
+		RFtempHumSensor1Signal:         -70,
+		LightBarBrightness:             2,
+	}
+
+	changes := daikin.DiffWithOptions(before, after, daikin.DiffOptions{
+		IgnoreFields:            []string{"lightBarBrightness"},
+		IgnoreTimestamps:        true,
+		IgnoreAirQualityHistory: true,
+		IgnoreSignalStrength:    true,
+	})
+
+	st.Expect(t, len(changes), 0)
+	st.Expect(t, len(daikin.Diff(before, after)), 5)
+}
@@ -0,0 +1,44 @@
+package daikin
+
+import (
+	"sync"
+	"time"
+)
+
+// RecordedWrite is an update that was built but not sent because the client
+// is in dry run mode.
+type RecordedWrite struct {
+	DeviceId string
+	Body     []byte
+	Time     time.Time
+}
+
+type recorder struct {
+	mu     sync.Mutex
+	writes []RecordedWrite
+}
+
+func (r *recorder) record(deviceId string, body []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.writes = append(r.writes, RecordedWrite{
+		DeviceId: deviceId,
+		Body:     append([]byte(nil), body...),
+		Time:     time.Now(),
+	})
+}
+
+func (d *Daikin) RecordedWrites() []RecordedWrite {
+	d.recorder.mu.Lock()
+	defer d.recorder.mu.Unlock()
+
+	return append([]RecordedWrite(nil), d.recorder.writes...)
+}
+
+func (d *Daikin) ClearRecordedWrites() {
+	d.recorder.mu.Lock()
+	defer d.recorder.mu.Unlock()
+
+	d.recorder.writes = nil
+}
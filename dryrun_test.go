@@ -0,0 +1,71 @@
+package daikin_test
+
+import (
+	"path"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/nbio/st"
+	"github.com/redgoose/daikin-skyport"
+)
+
+func TestDryRun(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Times(2).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	d := daikin.New(email, password)
+	d.DryRun = true
+
+	err := d.SetTemp(deviceId, daikin.SetTempParams{CoolSetpoint: 20, HeatSetpoint: 18})
+	st.Expect(t, err, nil)
+
+	err = d.SetMode(deviceId, daikin.ModeHeat)
+	st.Expect(t, err, nil)
+
+	err = d.UpdateDeviceRaw(deviceId, `{"lightBarBrightness": 2}`)
+	st.Expect(t, err, nil)
+
+	writes := d.RecordedWrites()
+	st.Expect(t, len(writes), 3)
+	st.Expect(t, writes[0].DeviceId, deviceId)
+	st.Expect(t, string(writes[0].Body), `{"cspHome":20,"hspHome":18,"schedOverride":1}`)
+	st.Expect(t, string(writes[1].Body), `{"mode":1}`)
+	st.Expect(t, string(writes[2].Body), `{"lightBarBrightness": 2}`)
+
+	d.ClearRecordedWrites()
+	st.Expect(t, len(d.RecordedWrites()), 0)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestDryRunValidation(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	d := daikin.New(email, password)
+	d.DryRun = true
+
+	err := d.SetCalibration(deviceId, 10, 0)
+	st.Expect(t, err != nil, true)
+	st.Expect(t, len(d.RecordedWrites()), 0)
+}
@@ -0,0 +1,156 @@
+package daikin
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	defaultEnergyMaxGap = 15 * time.Minute
+
+	// outdoor and rated power are reported in units of 10W
+	ctPowerScale        = 10
+	ctPowerNotAvailable = 0xFFFF
+)
+
+type DailyEnergy struct {
+	Date time.Time
+	// kWh per equipment status, including estimated usage
+	ByStatus map[EquipmentStatus]float64
+	Total    float64
+	// portion of Total estimated from rated power because no live reading was available
+	Estimated float64
+	Cost      float64
+}
+
+// EnergyMeter integrates power readings from successive DeviceInfo samples.
+// The power of a sample is applied until the next one; gaps longer than
+// MaxGap are skipped. Tariff is the price per kWh used for Cost. The zero
+// value is ready to use.
+type EnergyMeter struct {
+	MaxGap   time.Duration
+	Location *time.Location
+	Tariff   float64
+
+	days   map[time.Time]*DailyEnergy
+	last   *DeviceInfo
+	lastAt time.Time
+}
+
+func NewEnergyMeter() *EnergyMeter {
+	return &EnergyMeter{MaxGap: defaultEnergyMaxGap}
+}
+
+func (m *EnergyMeter) Add(at time.Time, deviceInfo *DeviceInfo) {
+	if m.days == nil {
+		m.days = map[time.Time]*DailyEnergy{}
+	}
+
+	if m.MaxGap <= 0 {
+		m.MaxGap = defaultEnergyMaxGap
+	}
+
+	if m.Location == nil {
+		m.Location = deviceInfo.Location()
+	}
+
+	if m.last != nil && at.After(m.lastAt) && at.Sub(m.lastAt) <= m.MaxGap {
+		watts, live := m.last.powerDraw()
+		m.accumulate(m.lastAt, at, m.last.EquipmentStatus, watts, !live)
+	}
+
+	if m.last == nil || at.After(m.lastAt) {
+		m.last = deviceInfo
+		m.lastAt = at
+	}
+}
+
+func (m *EnergyMeter) accumulate(from time.Time, to time.Time, status EquipmentStatus, watts float64, estimated bool) {
+	for from.Before(to) {
+		at := from.In(m.Location)
+		start := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, m.Location)
+		end := start.AddDate(0, 0, 1)
+		if end.After(to) {
+			end = to
+		}
+
+		day, ok := m.days[start]
+		if !ok {
+			day = &DailyEnergy{Date: start, ByStatus: map[EquipmentStatus]float64{}}
+			m.days[start] = day
+		}
+
+		kwh := watts * end.Sub(from).Hours() / 1000
+		day.ByStatus[status] += kwh
+		day.Total += kwh
+		if estimated {
+			day.Estimated += kwh
+		}
+
+		from = end
+	}
+}
+
+func (m *EnergyMeter) Days() []DailyEnergy {
+	days := make([]DailyEnergy, 0, len(m.days))
+	for _, day := range m.days {
+		d := *day
+		d.ByStatus = map[EquipmentStatus]float64{}
+		for status, kwh := range day.ByStatus {
+			d.ByStatus[status] = kwh
+		}
+		d.Cost = d.Total * m.Tariff
+		days = append(days, d)
+	}
+
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date.Before(days[j].Date)
+	})
+
+	return days
+}
+
+// powerDraw returns the current draw in watts and whether it comes from a
+// live reading rather than the rated power of the running equipment. When
+// only the indoor reading is available the outdoor unit is estimated from
+// its rated power.
+func (di *DeviceInfo) powerDraw() (float64, bool) {
+	if di.S21PowerConsumptionValid {
+		return float64(di.S21PowerConsumption), true
+	}
+
+	indoorOk := di.CtIndoorPower != ctPowerNotAvailable
+	outdoorOk := di.CtOutdoorPower != ctPowerNotAvailable
+
+	watts := 0
+	if indoorOk {
+		watts += di.CtIndoorPower
+	}
+	if outdoorOk {
+		watts += di.CtOutdoorPower * ctPowerScale
+	}
+
+	if watts == 0 {
+		return di.ratedPower(), false
+	}
+	if !outdoorOk {
+		return float64(watts) + di.ratedPower(), false
+	}
+
+	return float64(watts), indoorOk
+}
+
+// ratedPower returns the rated power in watts of the outdoor unit when its
+// compressor is running, or 0.
+func (di *DeviceInfo) ratedPower() float64 {
+	switch di.EquipmentStatus {
+	case EquipmentStatusCool, EquipmentStatusOvercool:
+		return float64(di.CtCoolingRatedPower * ctPowerScale)
+	case EquipmentStatusHeat:
+		if di.CtSystemCapCompressorHeat {
+			return float64(di.CtHeatingRatedPower * ctPowerScale)
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,83 @@
+package daikin_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nbio/st"
+	"github.com/redgoose/daikin-skyport"
+)
+
+func TestEnergyMeter(t *testing.T) {
+	start := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+	meter := daikin.NewEnergyMeter()
+	meter.MaxGap = time.Hour
+	meter.Tariff = 0.2
+
+	live := &daikin.DeviceInfo{TimeZone: "UTC", EquipmentStatus: daikin.EquipmentStatusCool, CtIndoorPower: 500, CtOutdoorPower: 150}
+	rated := &daikin.DeviceInfo{TimeZone: "UTC", EquipmentStatus: daikin.EquipmentStatusCool, CtCoolingRatedPower: 250}
+	idle := &daikin.DeviceInfo{TimeZone: "UTC", EquipmentStatus: daikin.EquipmentStatusIdle}
+
+	meter.Add(start, live)
+	meter.Add(start.Add(time.Hour), rated)
+	meter.Add(start.Add(90*time.Minute), idle)
+	meter.Add(start.Add(2*time.Hour), idle)
+
+	days := meter.Days()
+	st.Expect(t, len(days), 1)
+	st.Expect(t, days[0].Total, 3.25)
+	st.Expect(t, days[0].Estimated, 1.25)
+	st.Expect(t, days[0].ByStatus[daikin.EquipmentStatusCool], 3.25)
+	st.Expect(t, days[0].ByStatus[daikin.EquipmentStatusIdle], float64(0))
+	st.Expect(t, days[0].Cost, 0.65)
+}
+
+func TestEnergyMeterS21(t *testing.T) {
+	start := time.Date(2023, 9, 1, 23, 30, 0, 0, time.UTC)
+	meter := daikin.NewEnergyMeter()
+	meter.MaxGap = time.Hour
+
+	sample := &daikin.DeviceInfo{TimeZone: "UTC", EquipmentStatus: daikin.EquipmentStatusHeat, S21PowerConsumption: 1000, S21PowerConsumptionValid: true}
+
+	meter.Add(start, sample)
+	meter.Add(start.Add(time.Hour), sample)
+
+	days := meter.Days()
+	st.Expect(t, len(days), 2)
+	st.Expect(t, days[0].ByStatus[daikin.EquipmentStatusHeat], 0.5)
+	st.Expect(t, days[1].ByStatus[daikin.EquipmentStatusHeat], 0.5)
+	st.Expect(t, days[1].Estimated, float64(0))
+}
+
+func TestEnergyMeterZeroValue(t *testing.T) {
+	start := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+	var meter daikin.EnergyMeter
+
+	sample := &daikin.DeviceInfo{TimeZone: "UTC", EquipmentStatus: daikin.EquipmentStatusCool, CtIndoorPower: 500, CtOutdoorPower: 150}
+
+	meter.Add(start, sample)
+	meter.Add(start.Add(6*time.Minute), sample)
+
+	st.Expect(t, meter.MaxGap, 15*time.Minute)
+
+	days := meter.Days()
+	st.Expect(t, len(days), 1)
+	st.Expect(t, days[0].Total, 0.2)
+}
+
+func TestEnergyMeterOutdoorNotAvailable(t *testing.T) {
+	start := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+	meter := daikin.NewEnergyMeter()
+	meter.MaxGap = time.Hour
+
+	// the compressor is counted at its rated 2500 W next to the live indoor 500 W
+	sample := &daikin.DeviceInfo{TimeZone: "UTC", EquipmentStatus: daikin.EquipmentStatusCool, CtIndoorPower: 500, CtOutdoorPower: 0xFFFF, CtCoolingRatedPower: 250}
+
+	meter.Add(start, sample)
+	meter.Add(start.Add(time.Hour), sample)
+
+	days := meter.Days()
+	st.Expect(t, len(days), 1)
+	st.Expect(t, days[0].Total, 3.0)
+	st.Expect(t, days[0].Estimated, 3.0)
+}
@@ -0,0 +1,78 @@
+package daikin
+
+import (
+	"strconv"
+	"time"
+)
+
+const weatherForecastDays = 5
+
+type OutdoorAirQuality struct {
+	Level     AirQualityLevel
+	Value     int // air quality index
+	Ozone     int
+	Particles int
+}
+
+type ForecastDay struct {
+	Date        time.Time
+	Condition   WeatherCondition
+	Description string
+	Humidity    int
+	Temperature Temperature
+}
+
+type Environment struct {
+	OutdoorTemp     Temperature
+	OutdoorHumidity int
+	AirQuality      *OutdoorAirQuality
+	Forecast        []ForecastDay
+}
+
+func (di *DeviceInfo) Environment() Environment {
+	return di.EnvironmentAt(time.Now())
+}
+
+// EnvironmentAt dates the forecast relative to now in the device time zone.
+// The first forecast day is today; days without a forecast are left out.
+func (di *DeviceInfo) EnvironmentAt(now time.Time) Environment {
+	env := Environment{
+		OutdoorTemp:     Celsius(di.TempOutdoor),
+		OutdoorHumidity: di.HumOutdoor,
+	}
+
+	if di.AqOutdoorAvailable {
+		env.AirQuality = &OutdoorAirQuality{
+			Level:     AirQualityLevel(di.AqOutdoorLevel),
+			Value:     di.AqOutdoorValue,
+			Ozone:     di.AqOutdoorOzone,
+			Particles: di.AqOutdoorParticles,
+		}
+	}
+
+	now = now.In(di.Location())
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	for day := 0; day <= weatherForecastDays; day++ {
+		prefix := "WeatherToday"
+		if day > 0 {
+			prefix = "WeatherDay" + strconv.Itoa(day)
+		}
+
+		icon := di.stringField(prefix + "Icon")
+		description := di.stringField(prefix + "Cond")
+		if icon == "" && description == "" {
+			continue
+		}
+
+		env.Forecast = append(env.Forecast, ForecastDay{
+			Date:        today.AddDate(0, 0, day),
+			Condition:   ParseWeatherCondition(icon),
+			Description: description,
+			Humidity:    di.intField(prefix + "Hum"),
+			Temperature: Celsius(di.float32Field(prefix + "TempC")),
+		})
+	}
+
+	return env
+}
@@ -0,0 +1,58 @@
+package daikin_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nbio/st"
+	"github.com/redgoose/daikin-skyport"
+)
+
+func TestEnvironmentFixture(t *testing.T) {
+	deviceInfo := loadDeviceInfo(t)
+
+	// 02:30 UTC is still the previous day in New York
+	env := deviceInfo.EnvironmentAt(time.Date(2024, 7, 2, 2, 30, 0, 0, time.UTC))
+	loc := deviceInfo.Location()
+
+	st.Expect(t, env.OutdoorTemp, daikin.Celsius(17))
+	st.Expect(t, env.OutdoorHumidity, 88)
+
+	st.Expect(t, env.AirQuality.Level, daikin.AirQualityModerate)
+	st.Expect(t, env.AirQuality.Value, 57)
+	st.Expect(t, env.AirQuality.Ozone, 42)
+	st.Expect(t, env.AirQuality.Particles, 17)
+
+	st.Expect(t, len(env.Forecast), 6)
+
+	st.Expect(t, env.Forecast[0].Date, time.Date(2024, 7, 1, 0, 0, 0, 0, loc))
+	st.Expect(t, env.Forecast[0].Condition, daikin.WeatherClear)
+	st.Expect(t, env.Forecast[0].Description, "Clear")
+	st.Expect(t, env.Forecast[0].Temperature, daikin.Celsius(17))
+
+	st.Expect(t, env.Forecast[3].Date, time.Date(2024, 7, 4, 0, 0, 0, 0, loc))
+	st.Expect(t, env.Forecast[3].Condition, daikin.WeatherPartlyCloudy)
+	st.Expect(t, env.Forecast[3].Humidity, 72)
+	st.Expect(t, env.Forecast[3].Temperature, daikin.Celsius(25))
+
+	st.Expect(t, env.Forecast[5].Condition, daikin.WeatherSunny)
+}
+
+func TestEnvironmentMissingForecast(t *testing.T) {
+	deviceInfo := &daikin.DeviceInfo{
+		WeatherTodayIcon: "tstorms",
+		WeatherDay2Icon:  "snow",
+	}
+
+	env := deviceInfo.EnvironmentAt(time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC))
+	st.Expect(t, env.AirQuality == nil, true)
+	st.Expect(t, len(env.Forecast), 2)
+	st.Expect(t, env.Forecast[1].Date, time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC))
+	st.Expect(t, env.Forecast[1].Condition, daikin.WeatherSnow)
+}
+
+func TestParseWeatherCondition(t *testing.T) {
+	st.Expect(t, daikin.ParseWeatherCondition("MostlySunny"), daikin.WeatherMostlySunny)
+	st.Expect(t, daikin.ParseWeatherCondition("nt_clear"), daikin.WeatherUnknown)
+	st.Expect(t, daikin.WeatherChanceThunderstorms.String(), "chancetstorms")
+}
@@ -0,0 +1,268 @@
+package daikin
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// values reported by the equipment boards when a reading is not available
+const (
+	ctByteNotAvailable  = 255
+	ctShortNotAvailable = 32767
+)
+
+type BoardInfo struct {
+	UnitType        int
+	Model           string
+	Serial          string
+	SoftwareVersion string
+	CriticalFault   int
+	MinorFault      int
+}
+
+type Stat struct {
+	BoardInfo
+	ControlID         int
+	UISoftwareVersion string
+	OSSoftwareVersion string
+}
+
+type OutdoorUnit struct {
+	BoardInfo
+	InverterSoftwareVersion  string
+	Tonnage                  int
+	CoolDemand               int
+	HeatDemand               int
+	DehumidificationDemand   int
+	FanDemand                int
+	FanRPM                   int
+	CompressorFrequency      int
+	CompressorRunTime        time.Duration
+	Power                    int
+	SuctionPressure          *int
+	AirTemperature           *Temperature
+	CoilTemperature          *Temperature
+	LiquidTemperature        *Temperature
+	SuctionTemperature       *Temperature
+	DischargeTemperature     *Temperature
+	DefrostSensorTemperature *Temperature
+}
+
+type Furnace struct {
+	BoardInfo
+	Size                 int
+	GasHeatStages        int
+	OperatingMode        string
+	CoolDemand           int
+	HeatDemand           int
+	FanDemand            int
+	DehumidifyDemand     int
+	HumidifyDemand       int
+	Airflow              int
+	SupplyAirTemperature *Temperature
+	ReturnAirTemperature *Temperature
+	BoardTemperature     *Temperature
+}
+
+type AirHandler struct {
+	BoardInfo
+	Mode                 string
+	RefrigerantType      string
+	ElectricHeatKitSize  int
+	FanDemand            int
+	HeatDemand           int
+	DehumidifyDemand     int
+	HumidifyDemand       int
+	Airflow              int
+	Pressure             *int
+	LiquidTemperature    *Temperature
+	LiquidGasTemperature *Temperature
+	SuctionTemperature   *Temperature
+	SuperHeat            *TemperatureDelta
+	SubCool              *TemperatureDelta
+}
+
+type Coil struct {
+	BoardInfo
+	Pressure           *int
+	LiquidTemperature  *Temperature
+	SuctionTemperature *Temperature
+	SuperHeat          *TemperatureDelta
+	SubCool            *TemperatureDelta
+}
+
+// Board accessors return nil when the board does not report a unit type.
+// EquipmentCommunication is not used for this since devices with fully
+// communicating equipment have been seen reporting it as 0.
+
+func (di *DeviceInfo) Stat() *Stat {
+	if !boardPresent(di.CtStatUnitType) {
+		return nil
+	}
+
+	return &Stat{
+		BoardInfo: BoardInfo{
+			UnitType:        di.CtStatUnitType,
+			Model:           ctString(di.CtStatModelNoCharacter115),
+			Serial:          ctString(di.CtStatSerialNoCharacter115),
+			SoftwareVersion: ctString(di.CtStatCTSoftwareVersion),
+			CriticalFault:   di.CtStatCriticalFault,
+			MinorFault:      di.CtStatMinorFault,
+		},
+		ControlID:         di.CtStatControlID,
+		UISoftwareVersion: ctString(di.CtStatUISoftwareVersion),
+		OSSoftwareVersion: ctString(di.CtStatOSSoftwareVersion),
+	}
+}
+
+func (di *DeviceInfo) Outdoor() *OutdoorUnit {
+	if !boardPresent(di.CtOutdoorUnitType) {
+		return nil
+	}
+
+	return &OutdoorUnit{
+		BoardInfo: BoardInfo{
+			UnitType:        di.CtOutdoorUnitType,
+			Model:           ctString(di.CtOutdoorModelNoCharacter115),
+			Serial:          ctString(di.CtOutdoorSerialNoCharacter115),
+			SoftwareVersion: ctString(di.CtOutdoorControlSoftwareVersion),
+			CriticalFault:   di.CtOutdoorCriticalFault,
+			MinorFault:      di.CtOutdoorMinorFault,
+		},
+		InverterSoftwareVersion:  ctString(di.CtOutdoorInverterSoftwareVersion),
+		Tonnage:                  di.CtOutdoorTonnage,
+		CoolDemand:               di.CtOutdoorCoolRequestedDemand,
+		HeatDemand:               di.CtOutdoorHeatRequestedDemand,
+		DehumidificationDemand:   di.CtOutdoorDeHumidificationRequestedDemand,
+		FanDemand:                di.CtOutdoorFanRequestedDemandPercentage,
+		FanRPM:                   di.CtOutdoorFanRPM,
+		CompressorFrequency:      di.CtOutdoorFrequencyInPercent,
+		CompressorRunTime:        time.Duration(di.CtOutdoorCompressorRunTime) * time.Hour,
+		Power:                    di.CtOutdoorPower * ctPowerScale,
+		SuctionPressure:          ctPressure(di.CtOutdoorSuctionPressure),
+		AirTemperature:           ctTemperature(di.CtOutdoorAirTemperature),
+		CoilTemperature:          ctTemperature(di.CtOutdoorCoilTemperature),
+		LiquidTemperature:        ctTemperature(di.CtOutdoorLiquidTemperature),
+		SuctionTemperature:       ctTemperature(di.CtOutdoorSuctionTemperature),
+		DischargeTemperature:     ctTemperature(di.CtOutdoorDischargeTemperature),
+		DefrostSensorTemperature: ctTemperature(di.CtOutdoorDefrostSensorTemperature),
+	}
+}
+
+func (di *DeviceInfo) Furnace() *Furnace {
+	if !boardPresent(di.CtIFCUnitType) {
+		return nil
+	}
+
+	return &Furnace{
+		BoardInfo: BoardInfo{
+			UnitType:        di.CtIFCUnitType,
+			Model:           ctString(di.CtIFCModelNoCharacter115),
+			Serial:          ctString(di.CtIFCSerialNoCharacter115),
+			SoftwareVersion: ctString(di.CtIFCControlSoftwareVersion),
+			CriticalFault:   di.CtIFCCriticalFault,
+			MinorFault:      di.CtIFCMinorFault,
+		},
+		Size:                 di.CtIFCFurnaceSize,
+		GasHeatStages:        di.CtIFCNoofGasHeatStages,
+		OperatingMode:        ctString(di.CtIFCOperatingHeatCoolMode),
+		CoolDemand:           di.CtIFCCoolRequestedDemandPercent,
+		HeatDemand:           di.CtIFCHeatRequestedDemandPercent,
+		FanDemand:            di.CtIFCFanRequestedDemandPercent,
+		DehumidifyDemand:     di.CtIFCDehumRequestedDemandPercent,
+		HumidifyDemand:       di.CtIFCHumRequestedDemandPercent,
+		Airflow:              di.CtIFCIndoorBlowerAirflow,
+		SupplyAirTemperature: ctTemperature(di.CtIFCSupplyAirTemperature),
+		ReturnAirTemperature: ctTemperature(di.CtIFCReturnAirTemperature),
+		BoardTemperature:     ctTemperature(di.CtIFCBoardAirTemperature),
+	}
+}
+
+func (di *DeviceInfo) AirHandler() *AirHandler {
+	if !boardPresent(di.CtAHUnitType) {
+		return nil
+	}
+
+	return &AirHandler{
+		BoardInfo: BoardInfo{
+			UnitType:        di.CtAHUnitType,
+			Model:           ctString(di.CtAHModelNoCharacter115),
+			Serial:          ctString(di.CtAHSerialNoCharacter115),
+			SoftwareVersion: ctString(di.CtAHControlSoftwareVersion),
+			CriticalFault:   di.CtAHCriticalFault,
+			MinorFault:      di.CtAHMinorFault,
+		},
+		Mode:                 ctString(di.CtAHMode),
+		RefrigerantType:      ctString(di.CtAHRefrigerantType),
+		ElectricHeatKitSize:  di.CtAHElectricHeatKitSize,
+		FanDemand:            di.CtAHFanRequestedDemand,
+		HeatDemand:           di.CtAHHeatRequestedDemand,
+		DehumidifyDemand:     di.CtAHDehumidificationRequestedDemand,
+		HumidifyDemand:       di.CtAHHumidificationRequestedDemand,
+		Airflow:              di.CtAHCurrentIndoorAirflow,
+		Pressure:             ctPressure(di.CtAHPressureSensor),
+		LiquidTemperature:    ctTemperature(di.CtAHLiquidTemperature),
+		LiquidGasTemperature: ctTemperature(di.CtAHLiquidGasTemperature),
+		SuctionTemperature:   ctTemperature(di.CtAHSuctionTemperature),
+		SuperHeat:            ctTemperatureDelta(di.CtAHSuperHeatValue),
+		SubCool:              ctTemperatureDelta(di.CtAHSubCoolValue),
+	}
+}
+
+func (di *DeviceInfo) Coil() *Coil {
+	if !boardPresent(di.CtCoilUnitType) {
+		return nil
+	}
+
+	return &Coil{
+		BoardInfo: BoardInfo{
+			UnitType:        di.CtCoilUnitType,
+			Serial:          ctString(di.CtCoilSerialNoCharacter115),
+			SoftwareVersion: ctString(di.CtCoilControlSoftwareVersion),
+			CriticalFault:   di.CtEEVCoilCriticalFault,
+			MinorFault:      di.CtEEVCoilMinorFault,
+		},
+		Pressure:           ctPressure(di.CtEEVCoilPressureSensor),
+		LiquidTemperature:  ctTemperature(di.CtEEVCoilLiquidTemperature),
+		SuctionTemperature: ctTemperature(di.CtEEVCoilSuctionTemperature),
+		SuperHeat:          ctTemperatureDelta(di.CtEEVCoilSuperHeatValue),
+		SubCool:            ctTemperatureDelta(di.CtEEVCoilSubCoolValue),
+	}
+}
+
+func boardPresent(unitType int) bool {
+	return unitType != 0 && unitType != ctByteNotAvailable
+}
+
+// board strings are fixed width and filled with 0xFF when not set
+func ctString(s string) string {
+	if strings.ContainsRune(s, utf8.RuneError) {
+		return ""
+	}
+	return strings.TrimSpace(s)
+}
+
+// board temperatures are reported in tenths of a degree fahrenheit
+func ctTemperature(raw float32) *Temperature {
+	if raw == ctShortNotAvailable {
+		return nil
+	}
+	t := Temperature((raw/10 - 32) * 5 / 9)
+	return &t
+}
+
+func ctTemperatureDelta(raw int) *TemperatureDelta {
+	if raw == ctShortNotAvailable {
+		return nil
+	}
+	t := TemperatureDelta(float32(raw) / 10 * 5 / 9)
+	return &t
+}
+
+func ctPressure(raw int) *int {
+	if raw == ctShortNotAvailable {
+		return nil
+	}
+	return &raw
+}
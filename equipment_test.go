@@ -0,0 +1,57 @@
+package daikin_test
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/nbio/st"
+	"github.com/redgoose/daikin-skyport"
+)
+
+func loadDeviceInfo(t *testing.T) *daikin.DeviceInfo {
+	body, err := os.ReadFile(path.Join("fixtures", "device_info.json"))
+	st.Assert(t, err, nil)
+
+	var deviceInfo daikin.DeviceInfo
+	st.Assert(t, json.Unmarshal(body, &deviceInfo), nil)
+
+	return &deviceInfo
+}
+
+func round(value float32) float64 {
+	return math.Round(float64(value)*10) / 10
+}
+
+func TestEquipmentBoards(t *testing.T) {
+	deviceInfo := loadDeviceInfo(t)
+
+	outdoor := deviceInfo.Outdoor()
+	st.Expect(t, outdoor != nil, true)
+	st.Expect(t, outdoor.Model, "DZ6VSA3010")
+	st.Expect(t, outdoor.SoftwareVersion, "13C4")
+	st.Expect(t, outdoor.Tonnage, 5)
+	st.Expect(t, round(outdoor.AirTemperature.Fahrenheit()), 63.3)
+	st.Expect(t, round(outdoor.DischargeTemperature.Fahrenheit()), 110.1)
+	st.Expect(t, *outdoor.SuctionPressure, 109)
+
+	furnace := deviceInfo.Furnace()
+	st.Expect(t, furnace != nil, true)
+	st.Expect(t, furnace.SoftwareVersion, "1.07")
+	st.Expect(t, furnace.OperatingMode, "FAN COOL")
+	st.Expect(t, furnace.FanDemand, 83)
+	st.Expect(t, furnace.SupplyAirTemperature == nil, true)
+
+	coil := deviceInfo.Coil()
+	st.Expect(t, coil != nil, true)
+	st.Expect(t, coil.SoftwareVersion, "14002203_09")
+	st.Expect(t, round(coil.SuperHeat.Fahrenheit()), 45.6)
+
+	stat := deviceInfo.Stat()
+	st.Expect(t, stat != nil, true)
+	st.Expect(t, stat.UISoftwareVersion, "^0.0.22")
+
+	st.Expect(t, deviceInfo.AirHandler() == nil, true)
+}
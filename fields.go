@@ -0,0 +1,59 @@
+package daikin
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Many device values are repeated per sensor, unit or day with a number in
+// the field name. These helpers look them up by Go field name and return the
+// zero value when the field does not exist.
+
+func (di *DeviceInfo) field(name string) reflect.Value {
+	return reflect.ValueOf(di).Elem().FieldByName(name)
+}
+
+func (di *DeviceInfo) boolField(name string) bool {
+	f := di.field(name)
+	if !f.IsValid() || f.Kind() != reflect.Bool {
+		return false
+	}
+	return f.Bool()
+}
+
+func (di *DeviceInfo) intField(name string) int {
+	f := di.field(name)
+	if !f.IsValid() || !f.CanInt() {
+		return 0
+	}
+	return int(f.Int())
+}
+
+func (di *DeviceInfo) float32Field(name string) float32 {
+	f := di.field(name)
+	if !f.IsValid() || !f.CanFloat() {
+		return 0
+	}
+	return float32(f.Float())
+}
+
+func (di *DeviceInfo) stringField(name string) string {
+	f := di.field(name)
+	if !f.IsValid() || f.Kind() != reflect.String {
+		return ""
+	}
+	return f.String()
+}
+
+// jsonName returns the name a DeviceInfo field has in the API, or "" when it
+// is not sent.
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,78 @@
+package daikin
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// RequestP1P2FieldSettingMode asks the equipment to load a field setting mode
+// into the SW registers of the group. It requires InstallerMode and refuses
+// to run while a previous mode change is still pending.
+func (d *Daikin) RequestP1P2FieldSettingMode(deviceId string, group FieldSettingGroup, mode int) error {
+	if !d.InstallerMode {
+		return errors.New("installer mode is required to change field settings")
+	}
+
+	prefix, ok := fieldSettingPrefixes[group]
+	if !ok {
+		return errors.New("invalid field setting group")
+	}
+
+	if mode < 0 || mode > fieldSettingModeMax {
+		return errors.New("field setting mode outside of allowable range")
+	}
+
+	deviceInfo, err := d.GetDeviceInfo(deviceId)
+	if err != nil {
+		return errors.New("get device info failed")
+	}
+
+	if deviceInfo.boolField(prefix + "FieldSettingModeNumChangeRequest") {
+		return errors.New("a field setting mode change is already pending")
+	}
+
+	data := map[string]interface{}{
+		prefix + "FieldSettingModeNumber":           mode,
+		prefix + "FieldSettingModeNumChangeRequest": true,
+	}
+
+	json, err := json.Marshal(data)
+	if err != nil {
+		return errors.New("json marshal failed")
+	}
+
+	return d.updateDevice(deviceId, json)
+}
+
+// RequestP1P2FieldSettingUnit selects the indoor unit the public field
+// settings apply to. It has the same safeguards as RequestP1P2FieldSettingMode.
+func (d *Daikin) RequestP1P2FieldSettingUnit(deviceId string, unit int) error {
+	if !d.InstallerMode {
+		return errors.New("installer mode is required to change field settings")
+	}
+
+	if unit < 0 || unit > fieldSettingUnitMax {
+		return errors.New("field setting unit outside of allowable range")
+	}
+
+	deviceInfo, err := d.GetDeviceInfo(deviceId)
+	if err != nil {
+		return errors.New("get device info failed")
+	}
+
+	if deviceInfo.P1P2FieldSettingUnitNumChangeRequest {
+		return errors.New("a field setting unit change is already pending")
+	}
+
+	data := map[string]interface{}{
+		"P1P2FieldSettingUnitNum":              unit,
+		"P1P2FieldSettingUnitNumChangeRequest": true,
+	}
+
+	json, err := json.Marshal(data)
+	if err != nil {
+		return errors.New("json marshal failed")
+	}
+
+	return d.updateDevice(deviceId, json)
+}
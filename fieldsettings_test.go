@@ -0,0 +1,97 @@
+package daikin_test
+
+import (
+	"errors"
+	"path"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/nbio/st"
+	"github.com/redgoose/daikin-skyport"
+)
+
+func TestRequestP1P2FieldSettingMode(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{"P1P2NonPublicFieldSettingModeNumber": 21, "P1P2NonPublicFieldSettingModeNumChangeRequest": true}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	d := daikin.New(email, password)
+
+	err := d.RequestP1P2FieldSettingMode(deviceId, daikin.FieldSettingNonPublic, 21)
+	st.Expect(t, err, errors.New("installer mode is required to change field settings"))
+
+	d.InstallerMode = true
+
+	err = d.RequestP1P2FieldSettingMode(deviceId, daikin.FieldSettingNonPublic, 256)
+	st.Expect(t, err, errors.New("field setting mode outside of allowable range"))
+
+	err = d.RequestP1P2FieldSettingMode(deviceId, daikin.FieldSettingNonPublic, 21)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestRequestP1P2FieldSettingUnit(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{"P1P2FieldSettingUnitNum": 3, "P1P2FieldSettingUnitNumChangeRequest": true}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	d := daikin.New(email, password)
+
+	err := d.RequestP1P2FieldSettingUnit(deviceId, 3)
+	st.Expect(t, err, errors.New("installer mode is required to change field settings"))
+
+	d.InstallerMode = true
+
+	err = d.RequestP1P2FieldSettingUnit(deviceId, 16)
+	st.Expect(t, err, errors.New("field setting unit outside of allowable range"))
+
+	err = d.RequestP1P2FieldSettingUnit(deviceId, 3)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
@@ -0,0 +1,144 @@
+package openadr
+
+import (
+	"errors"
+	"time"
+)
+
+type EventStatus string
+
+const (
+	StatusNone      EventStatus = "none"
+	StatusFar       EventStatus = "far"
+	StatusNear      EventStatus = "near"
+	StatusActive    EventStatus = "active"
+	StatusCompleted EventStatus = "completed"
+	StatusCancelled EventStatus = "cancelled"
+)
+
+type OptType string
+
+const (
+	OptIn  OptType = "optIn"
+	OptOut OptType = "optOut"
+)
+
+// Event is a demand response event as distributed by a VTN. Level is the
+// value of the SIMPLE signal, from 0 (normal) to 3 (special); Price is set
+// when the event carries an ELECTRICITY_PRICE signal. A zero Duration means
+// the event has no set end and runs until it is cancelled.
+type Event struct {
+	ID                 string
+	ModificationNumber int
+	Status             EventStatus
+	Start              time.Time
+	Duration           time.Duration
+	Level              int
+	Price              *float64
+	ResponseRequired   bool
+}
+
+// End returns the zero time for an open-ended event.
+func (e Event) End() time.Time {
+	if e.OpenEnded() {
+		return time.Time{}
+	}
+	return e.Start.Add(e.Duration)
+}
+
+func (e Event) OpenEnded() bool {
+	return e.Duration == 0
+}
+
+// ActiveAt reports whether the event is in effect at t, going by its active
+// period rather than the status sent with it.
+func (e Event) ActiveAt(t time.Time) bool {
+	if e.Status == StatusCancelled || e.Status == StatusCompleted {
+		return false
+	}
+	return !t.Before(e.Start) && !e.endedAt(t)
+}
+
+func (e Event) pendingAt(t time.Time) bool {
+	if e.Status == StatusCancelled || e.Status == StatusCompleted {
+		return false
+	}
+	return !e.endedAt(t)
+}
+
+func (e Event) endedAt(t time.Time) bool {
+	return !e.OpenEnded() && !t.Before(e.End())
+}
+
+func decodeEvent(o oadrEvent) (Event, error) {
+	start, err := time.Parse(time.RFC3339, o.Event.ActivePeriod.Properties.Start.DateTime)
+	if err != nil {
+		return Event{}, errors.New("invalid event start")
+	}
+
+	duration, err := parseDuration(o.Event.ActivePeriod.Properties.Duration.Duration)
+	if err != nil {
+		return Event{}, err
+	}
+
+	e := Event{
+		ID:                 o.Event.Descriptor.EventID,
+		ModificationNumber: o.Event.Descriptor.ModificationNumber,
+		Status:             EventStatus(o.Event.Descriptor.EventStatus),
+		Start:              start,
+		Duration:           duration,
+		ResponseRequired:   o.ResponseRequired != "never",
+	}
+
+	for _, signal := range o.Event.Signals {
+		switch signal.SignalName {
+		case signalSimple:
+			e.Level = int(signal.CurrentValue)
+		case signalPrice:
+			price := signal.CurrentValue
+			e.Price = &price
+		}
+	}
+
+	return e, nil
+}
+
+func encodeEvent(e Event) oadrEvent {
+	o := oadrEvent{
+		Event: eiEvent{
+			Descriptor: eventDescriptor{
+				EventID:            e.ID,
+				ModificationNumber: e.ModificationNumber,
+				EventStatus:        string(e.Status),
+			},
+			ActivePeriod: activePeriod{
+				Properties: properties{
+					Start:    icalStart{DateTime: e.Start.UTC().Format(time.RFC3339)},
+					Duration: icalDuration{Duration: formatDuration(e.Duration)},
+				},
+			},
+			Signals: []eventSignal{{
+				SignalName:   signalSimple,
+				SignalType:   "level",
+				SignalID:     "0",
+				CurrentValue: float64(e.Level),
+			}},
+		},
+		ResponseRequired: "always",
+	}
+
+	if e.Price != nil {
+		o.Event.Signals = append(o.Event.Signals, eventSignal{
+			SignalName:   signalPrice,
+			SignalType:   "price",
+			SignalID:     "1",
+			CurrentValue: *e.Price,
+		})
+	}
+
+	if !e.ResponseRequired {
+		o.ResponseRequired = "never"
+	}
+
+	return o
+}
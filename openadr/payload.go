@@ -0,0 +1,209 @@
+package openadr
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Only the parts of the OpenADR 2.0b schema needed to poll for events and
+// report opt status are modelled here.
+
+type payload struct {
+	XMLName      xml.Name     `xml:"http://openadr.org/oadr-2.0b/2012/07 oadrPayload"`
+	SignedObject signedObject `xml:"http://openadr.org/oadr-2.0b/2012/07 oadrSignedObject"`
+}
+
+type signedObject struct {
+	Poll            *oadrPoll            `xml:"http://openadr.org/oadr-2.0b/2012/07 oadrPoll,omitempty"`
+	DistributeEvent *oadrDistributeEvent `xml:"http://openadr.org/oadr-2.0b/2012/07 oadrDistributeEvent,omitempty"`
+	CreatedEvent    *oadrCreatedEvent    `xml:"http://openadr.org/oadr-2.0b/2012/07 oadrCreatedEvent,omitempty"`
+	Response        *oadrResponse        `xml:"http://openadr.org/oadr-2.0b/2012/07 oadrResponse,omitempty"`
+}
+
+type oadrPoll struct {
+	SchemaVersion string `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 schemaVersion,attr"`
+	VenID         string `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 venID"`
+}
+
+type oadrResponse struct {
+	SchemaVersion string     `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 schemaVersion,attr"`
+	Response      eiResponse `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 eiResponse"`
+	VenID         string     `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 venID,omitempty"`
+}
+
+type eiResponse struct {
+	ResponseCode string `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 responseCode"`
+	RequestID    string `xml:"http://docs.oasis-open.org/ns/energyinterop/201110/payloads requestID"`
+}
+
+type oadrDistributeEvent struct {
+	SchemaVersion string      `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 schemaVersion,attr"`
+	RequestID     string      `xml:"http://docs.oasis-open.org/ns/energyinterop/201110/payloads requestID"`
+	VtnID         string      `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 vtnID"`
+	Events        []oadrEvent `xml:"http://openadr.org/oadr-2.0b/2012/07 oadrEvent"`
+}
+
+type oadrEvent struct {
+	Event            eiEvent `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 eiEvent"`
+	ResponseRequired string  `xml:"http://openadr.org/oadr-2.0b/2012/07 oadrResponseRequired"`
+}
+
+type eiEvent struct {
+	Descriptor   eventDescriptor `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 eventDescriptor"`
+	ActivePeriod activePeriod    `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 eiActivePeriod"`
+	Signals      []eventSignal   `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 eiEventSignals>eiEventSignal"`
+}
+
+type eventDescriptor struct {
+	EventID            string `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 eventID"`
+	ModificationNumber int    `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 modificationNumber"`
+	EventStatus        string `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 eventStatus"`
+}
+
+// Parent elements in a tag path are marshalled in the namespace of the
+// enclosing element, so elements in another namespace get their own type.
+type activePeriod struct {
+	Properties properties `xml:"urn:ietf:params:xml:ns:icalendar-2.0 properties"`
+}
+
+type properties struct {
+	Start    icalStart    `xml:"urn:ietf:params:xml:ns:icalendar-2.0 dtstart"`
+	Duration icalDuration `xml:"urn:ietf:params:xml:ns:icalendar-2.0 duration"`
+}
+
+type icalStart struct {
+	DateTime string `xml:"urn:ietf:params:xml:ns:icalendar-2.0 date-time"`
+}
+
+type icalDuration struct {
+	Duration string `xml:"urn:ietf:params:xml:ns:icalendar-2.0 duration"`
+}
+
+type eventSignal struct {
+	SignalName   string  `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 signalName"`
+	SignalType   string  `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 signalType"`
+	SignalID     string  `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 signalID"`
+	CurrentValue float64 `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 currentValue>payloadFloat>value"`
+}
+
+type oadrCreatedEvent struct {
+	SchemaVersion string         `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 schemaVersion,attr"`
+	CreatedEvent  eiCreatedEvent `xml:"http://docs.oasis-open.org/ns/energyinterop/201110/payloads eiCreatedEvent"`
+}
+
+type eiCreatedEvent struct {
+	Response       eiResponse     `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 eiResponse"`
+	EventResponses eventResponses `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 eventResponses"`
+	VenID          string         `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 venID"`
+}
+
+type eventResponses struct {
+	Responses []eventResponse `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 eventResponse"`
+}
+
+type eventResponse struct {
+	ResponseCode       string `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 responseCode"`
+	RequestID          string `xml:"http://docs.oasis-open.org/ns/energyinterop/201110/payloads requestID"`
+	EventID            string `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 qualifiedEventID>eventID"`
+	ModificationNumber int    `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 qualifiedEventID>modificationNumber"`
+	OptType            string `xml:"http://docs.oasis-open.org/ns/energyinterop/201110 optType"`
+}
+
+const (
+	schemaVersion = "2.0b"
+	responseOK    = "200"
+
+	signalSimple = "SIMPLE"
+	signalPrice  = "ELECTRICITY_PRICE"
+)
+
+// parseDuration reads the iCalendar durations used by OpenADR, such as
+// PT1H30M or P1D.
+func parseDuration(s string) (time.Duration, error) {
+	rest, ok := strings.CutPrefix(s, "P")
+	if !ok {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	var d time.Duration
+	inTime := false
+	for rest != "" {
+		if rest[0] == 'T' {
+			inTime = true
+			rest = rest[1:]
+			continue
+		}
+
+		i := strings.IndexAny(rest, "WDHMS")
+		if i <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+
+		n, err := strconv.Atoi(rest[:i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+
+		unit := time.Duration(0)
+		switch {
+		case rest[i] == 'W' && !inTime:
+			unit = 7 * 24 * time.Hour
+		case rest[i] == 'D' && !inTime:
+			unit = 24 * time.Hour
+		case rest[i] == 'H' && inTime:
+			unit = time.Hour
+		case rest[i] == 'M' && inTime:
+			unit = time.Minute
+		case rest[i] == 'S' && inTime:
+			unit = time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+
+		d += time.Duration(n) * unit
+		rest = rest[i+1:]
+	}
+
+	return d, nil
+}
+
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d <= 0 {
+		return "PT0S"
+	}
+
+	s := "PT"
+	if h := d / time.Hour; h > 0 {
+		s += strconv.Itoa(int(h)) + "H"
+		d -= h * time.Hour
+	}
+	if m := d / time.Minute; m > 0 {
+		s += strconv.Itoa(int(m)) + "M"
+		d -= m * time.Minute
+	}
+	if d > 0 {
+		s += strconv.Itoa(int(d/time.Second)) + "S"
+	}
+	return s
+}
+
+func marshalPayload(o signedObject) ([]byte, error) {
+	body, err := xml.Marshal(payload{SignedObject: o})
+	if err != nil {
+		return nil, errors.New("xml marshal failed")
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+func unmarshalPayload(body []byte) (signedObject, error) {
+	var p payload
+	if err := xml.Unmarshal(body, &p); err != nil {
+		return signedObject{}, errors.New("xml unmarshal failed")
+	}
+	return p.SignedObject, nil
+}
@@ -0,0 +1,408 @@
+// Package openadr enrolls a Daikin One+ thermostat in OpenADR 2.0b demand
+// response programs directly, without the vendor's cloud program. A VEN
+// polls the utility or aggregator's VTN, writes the current event to the
+// thermostat's OpenADR fields and setpoints, and reports whether the
+// thermostat is taking part.
+package openadr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redgoose/daikin-skyport"
+)
+
+const (
+	defaultPollInterval = 30 * time.Second
+	defaultLevelOffset  = daikin.TemperatureDelta(1)
+
+	maxSimpleLevel = 3
+)
+
+type setpoints struct {
+	cool          float32
+	heat          float32
+	schedOverride int
+}
+
+// appliedState is what was last written to the thermostat
+type appliedState struct {
+	eventID            string
+	modificationNumber int
+	active             bool
+	opt                OptType
+}
+
+type report struct {
+	modificationNumber int
+	opt                OptType
+}
+
+// VEN is a virtual end node for a single thermostat. During an active event
+// the cooling setpoint is raised and the heating setpoint lowered by
+// LevelOffset for each level of the SIMPLE signal, within the thermostat's
+// OpenADR global limits. The original setpoints are restored when the event
+// ends, unless the thermostat was opted out in the meantime.
+//
+// The thermostat is reported as opted out while OpenADR is disabled on it,
+// and for the active event once the occupant overrides it.
+type VEN struct {
+	URL         string // VTN base URL, e.g. https://vtn.example.com/OpenADR2/Simple/2.0b
+	VenID       string
+	DeviceID    string
+	Interval    time.Duration
+	LevelOffset daikin.TemperatureDelta
+	Client      *http.Client
+	OnError     func(error)
+
+	daikin *daikin.Daikin
+
+	mu       sync.Mutex
+	events   map[string]Event
+	reported map[string]report
+	applied  *appliedState
+	saved    *setpoints
+}
+
+func NewVEN(d *daikin.Daikin, deviceId string, url string, venId string) *VEN {
+	return &VEN{
+		URL:         url,
+		VenID:       venId,
+		DeviceID:    deviceId,
+		Interval:    defaultPollInterval,
+		LevelOffset: defaultLevelOffset,
+		Client:      &http.Client{Timeout: 10 * time.Second},
+		daikin:      d,
+		events:      map[string]Event{},
+		reported:    map[string]report{},
+	}
+}
+
+// Events returns the events last distributed by the VTN, ordered by start.
+func (v *VEN) Events() []Event {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	events := make([]Event, 0, len(v.events))
+	for _, e := range v.events {
+		events = append(events, e)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+	return events
+}
+
+// Run polls the VTN every Interval until ctx is cancelled. Poll errors are
+// passed to OnError and do not stop the VEN. A zero Interval falls back to
+// the default.
+func (v *VEN) Run(ctx context.Context) error {
+	interval := v.Interval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := v.Poll(ctx); err != nil && v.OnError != nil && ctx.Err() == nil {
+			v.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll runs a single poll cycle: fetch events from the VTN, update the
+// thermostat and report opt status for new or changed events.
+func (v *VEN) Poll(ctx context.Context) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	res, err := v.post(ctx, "/OadrPoll", signedObject{
+		Poll: &oadrPoll{SchemaVersion: schemaVersion, VenID: v.VenID},
+	})
+	if err != nil {
+		return err
+	}
+
+	if res.DistributeEvent != nil {
+		if err := v.distribute(res.DistributeEvent); err != nil {
+			return err
+		}
+	}
+
+	deviceInfo, err := v.daikin.GetDeviceInfo(v.DeviceID)
+	if err != nil {
+		return errors.New("get device info failed")
+	}
+
+	now := time.Now()
+
+	if err := v.apply(deviceInfo, now); err != nil {
+		return err
+	}
+
+	return v.report(ctx, deviceInfo, now)
+}
+
+// distribute replaces the known events. Events the VTN no longer sends are
+// considered cancelled.
+func (v *VEN) distribute(d *oadrDistributeEvent) error {
+	events := map[string]Event{}
+	for _, o := range d.Events {
+		e, err := decodeEvent(o)
+		if err != nil {
+			return err
+		}
+		events[e.ID] = e
+	}
+
+	for id := range v.reported {
+		if _, ok := events[id]; !ok {
+			delete(v.reported, id)
+		}
+	}
+
+	v.events = events
+	return nil
+}
+
+// current returns the active event, or the next one to start.
+func (v *VEN) current(now time.Time) *Event {
+	var next *Event
+	for _, e := range v.events {
+		e := e
+		if e.ActiveAt(now) {
+			return &e
+		}
+		if e.pendingAt(now) && (next == nil || e.Start.Before(next.Start)) {
+			next = &e
+		}
+	}
+	return next
+}
+
+func (v *VEN) opt(deviceInfo *daikin.DeviceInfo, e Event, now time.Time) OptType {
+	if !deviceInfo.OpenADRenabled {
+		return OptOut
+	}
+	if e.ActiveAt(now) && deviceInfo.OpenADRoverrideState != 0 {
+		return OptOut
+	}
+	return OptIn
+}
+
+func (v *VEN) apply(deviceInfo *daikin.DeviceInfo, now time.Time) error {
+	e := v.current(now)
+
+	var state *appliedState
+	active := false
+	opt := OptIn
+	if e != nil {
+		active = e.ActiveAt(now)
+		opt = v.opt(deviceInfo, *e, now)
+		state = &appliedState{e.ID, e.ModificationNumber, active, opt}
+	}
+
+	if state == nil && v.applied == nil || state != nil && v.applied != nil && *state == *v.applied {
+		return nil
+	}
+
+	if opt == OptOut {
+		// leave the occupant's setpoints alone from here on
+		v.saved = nil
+	}
+
+	data := map[string]interface{}{}
+
+	if e == nil {
+		data["OpenADReventID"] = ""
+		data["OpenADReventLevel"] = 0
+		data["OpenADReventPrice"] = 0
+		data["OpenADReventStart"] = 0
+		data["OpenADReventStop"] = 0
+		data["OpenADReventActualStop"] = now.Unix()
+	} else {
+		data["OpenADReventID"] = e.ID
+		data["OpenADReventLevel"] = simpleLevel(e.Level)
+		data["OpenADReventStart"] = e.Start.Unix()
+		data["OpenADReventStop"] = 0
+		if !e.OpenEnded() {
+			data["OpenADReventStop"] = e.End().Unix()
+		}
+		if e.Price != nil {
+			data["OpenADReventPrice"] = int(math.Round(*e.Price))
+		}
+	}
+
+	switch {
+	case active && opt == OptIn:
+		if v.saved == nil {
+			v.saved = &setpoints{
+				cool:          deviceInfo.CspHome,
+				heat:          deviceInfo.HspHome,
+				schedOverride: deviceInfo.SchedOverride,
+			}
+		}
+
+		offset := float64(v.LevelOffset) * float64(simpleLevel(e.Level))
+		cool := halfDegree(float64(v.saved.cool) + offset)
+		heat := halfDegree(float64(v.saved.heat) - offset)
+
+		// the global limits never move a setpoint toward more cooling or heating
+		if limit := deviceInfo.OpenADRglobalTempMax; limit > 0 && cool > limit {
+			cool = float32(math.Max(float64(limit), float64(v.saved.cool)))
+		}
+		if limit := deviceInfo.OpenADRglobalTempMin; limit > 0 && heat < limit {
+			heat = float32(math.Min(float64(limit), float64(v.saved.heat)))
+		}
+
+		caps := deviceInfo.Capabilities()
+		if caps.CoolSetpointMax <= caps.CoolSetpointMin || caps.HeatSetpointMax <= caps.HeatSetpointMin {
+			return errors.New("device setpoint range not available")
+		}
+		cool = clampSetpoint(cool, caps.CoolSetpointMin, caps.CoolSetpointMax)
+		heat = clampSetpoint(heat, caps.HeatSetpointMin, caps.HeatSetpointMax)
+
+		data["OpenADReventTempMax"] = cool
+		data["OpenADReventTempMin"] = heat
+		data["cspHome"] = cool
+		data["hspHome"] = heat
+		data["schedOverride"] = 1
+	case v.saved != nil:
+		// the event ended, possibly with the next one already pending
+		data["cspHome"] = v.saved.cool
+		data["hspHome"] = v.saved.heat
+		data["schedOverride"] = v.saved.schedOverride
+		v.saved = nil
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return errors.New("json marshal failed")
+	}
+
+	if err := v.daikin.UpdateDeviceRaw(v.DeviceID, string(body)); err != nil {
+		return err
+	}
+
+	v.applied = state
+	return nil
+}
+
+func (v *VEN) report(ctx context.Context, deviceInfo *daikin.DeviceInfo, now time.Time) error {
+	var responses []eventResponse
+	sent := map[string]report{}
+
+	for _, e := range v.events {
+		if !e.ResponseRequired {
+			continue
+		}
+
+		r := report{modificationNumber: e.ModificationNumber, opt: v.opt(deviceInfo, e, now)}
+		if last, ok := v.reported[e.ID]; ok && last == r {
+			continue
+		}
+
+		responses = append(responses, eventResponse{
+			ResponseCode:       responseOK,
+			EventID:            e.ID,
+			ModificationNumber: e.ModificationNumber,
+			OptType:            string(r.opt),
+		})
+		sent[e.ID] = r
+	}
+
+	if len(responses) == 0 {
+		return nil
+	}
+
+	res, err := v.post(ctx, "/EiEvent", signedObject{
+		CreatedEvent: &oadrCreatedEvent{
+			SchemaVersion: schemaVersion,
+			CreatedEvent: eiCreatedEvent{
+				Response:       eiResponse{ResponseCode: responseOK},
+				EventResponses: eventResponses{Responses: responses},
+				VenID:          v.VenID,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if res.Response == nil || res.Response.Response.ResponseCode != responseOK {
+		return errors.New("vtn rejected event response")
+	}
+
+	for id, r := range sent {
+		v.reported[id] = r
+	}
+	return nil
+}
+
+func (v *VEN) post(ctx context.Context, service string, o signedObject) (signedObject, error) {
+	body, err := marshalPayload(o)
+	if err != nil {
+		return signedObject{}, err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, v.URL+service, bytes.NewReader(body))
+	if err != nil {
+		return signedObject{}, errors.New("http.NewRequest failed")
+	}
+	r.Header.Set("Content-Type", "application/xml")
+
+	res, err := v.Client.Do(r)
+	if err != nil {
+		return signedObject{}, errors.New("http request failed")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return signedObject{}, errors.New("vtn request failed with status " + res.Status)
+	}
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return signedObject{}, errors.New("reading vtn response failed")
+	}
+
+	return unmarshalPayload(resBody)
+}
+
+func simpleLevel(level int) int {
+	if level < 0 {
+		return 0
+	}
+	if level > maxSimpleLevel {
+		return maxSimpleLevel
+	}
+	return level
+}
+
+// setpoints are set in 0.5 °C steps
+func halfDegree(v float64) float32 {
+	return float32(math.Round(v*2) / 2)
+}
+
+// clampSetpoint keeps a setpoint on a 0.5 °C step within the device's range.
+func clampSetpoint(v float32, min daikin.Temperature, max daikin.Temperature) float32 {
+	lo := math.Ceil(float64(min.Celsius())*2) / 2
+	hi := math.Floor(float64(max.Celsius())*2) / 2
+	return float32(math.Max(lo, math.Min(hi, float64(halfDegree(float64(v))))))
+}
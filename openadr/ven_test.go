@@ -0,0 +1,432 @@
+package openadr_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/nbio/st"
+	"github.com/redgoose/daikin-skyport"
+	"github.com/redgoose/daikin-skyport/openadr"
+)
+
+const (
+	urlBase  = "https://api.daikinskyport.com"
+	email    = "test@test.com"
+	password = "mypassword"
+	deviceId = "0000000-0000-0000-0000-000000000000"
+)
+
+func mockDaikin(devices ...map[string]interface{}) *daikin.Daikin {
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": "foo", "accessTokenExpiresIn": 3600})
+
+	for i, device := range devices {
+		mock := gock.New(urlBase).Get("/deviceData/" + deviceId)
+		if i == len(devices)-1 {
+			mock.Persist()
+		}
+		mock.Reply(200).JSON(device)
+	}
+
+	d := daikin.New(email, password)
+	d.DryRun = true
+	return d
+}
+
+// the VTN runs on a real listener, so keep its traffic away from gock
+func newVEN(d *daikin.Daikin, url string) *openadr.VEN {
+	ven := openadr.NewVEN(d, deviceId, url+"/OpenADR2/Simple/2.0b", "ven-1")
+	ven.Client = &http.Client{Transport: &http.Transport{}}
+	return ven
+}
+
+func writes(t *testing.T, d *daikin.Daikin) []map[string]interface{} {
+	var out []map[string]interface{}
+	for _, w := range d.RecordedWrites() {
+		var data map[string]interface{}
+		st.Expect(t, json.Unmarshal(w.Body, &data), nil)
+		out = append(out, data)
+	}
+	d.ClearRecordedWrites()
+	return out
+}
+
+func thermostat() map[string]interface{} {
+	return map[string]interface{}{
+		"OpenADRenabled":       true,
+		"OpenADRoverrideState": 0,
+		"OpenADRglobalTempMin": 10,
+		"OpenADRglobalTempMax": 26.5,
+		"tempSPMin":            10,
+		"tempSPMax":            32,
+		"cspHome":              24,
+		"hspHome":              20,
+		"schedOverride":        0,
+	}
+}
+
+func TestVEN(t *testing.T) {
+	defer gock.Off()
+
+	d := mockDaikin(thermostat())
+
+	vtn := openadr.NewLocalVTN()
+	server := httptest.NewServer(vtn)
+	defer server.Close()
+
+	ven := newVEN(d, server.URL)
+	ctx := context.Background()
+
+	price := 12.4
+	start := time.Now().Add(-time.Minute).Truncate(time.Second)
+	vtn.SetEvent(openadr.Event{
+		ID:               "evt-1",
+		Start:            start,
+		Duration:         time.Hour,
+		Level:            2,
+		Price:            &price,
+		ResponseRequired: true,
+	})
+
+	st.Expect(t, ven.Poll(ctx), nil)
+
+	events := ven.Events()
+	st.Expect(t, len(events), 1)
+	st.Expect(t, events[0].Status, openadr.StatusActive)
+	st.Expect(t, events[0].Start.Equal(start), true)
+	st.Expect(t, events[0].Duration, time.Hour)
+
+	w := writes(t, d)
+	st.Expect(t, len(w), 1)
+	st.Expect(t, w[0]["OpenADReventID"], "evt-1")
+	st.Expect(t, w[0]["OpenADReventLevel"], 2.0)
+	st.Expect(t, w[0]["OpenADReventPrice"], 12.0)
+	st.Expect(t, w[0]["OpenADReventStart"], float64(start.Unix()))
+	st.Expect(t, w[0]["cspHome"], 26.0)
+	st.Expect(t, w[0]["hspHome"], 18.0)
+	st.Expect(t, w[0]["schedOverride"], 1.0)
+
+	responses := vtn.Responses()
+	st.Expect(t, len(responses), 1)
+	st.Expect(t, responses[0].VenID, "ven-1")
+	st.Expect(t, responses[0].EventID, "evt-1")
+	st.Expect(t, responses[0].Opt, openadr.OptIn)
+
+	// nothing changed
+	st.Expect(t, ven.Poll(ctx), nil)
+	st.Expect(t, len(writes(t, d)), 0)
+	st.Expect(t, len(vtn.Responses()), 1)
+
+	vtn.CancelEvent("evt-1")
+	st.Expect(t, ven.Poll(ctx), nil)
+
+	w = writes(t, d)
+	st.Expect(t, len(w), 1)
+	st.Expect(t, w[0]["OpenADReventID"], "")
+	st.Expect(t, w[0]["cspHome"], 24.0)
+	st.Expect(t, w[0]["hspHome"], 20.0)
+	st.Expect(t, w[0]["schedOverride"], 0.0)
+
+	responses = vtn.Responses()
+	st.Expect(t, len(responses), 2)
+	st.Expect(t, responses[1].ModificationNumber, 1)
+}
+
+func TestVENGlobalLimits(t *testing.T) {
+	defer gock.Off()
+
+	d := mockDaikin(thermostat())
+
+	vtn := openadr.NewLocalVTN()
+	server := httptest.NewServer(vtn)
+	defer server.Close()
+
+	ven := newVEN(d, server.URL)
+	vtn.SetEvent(openadr.Event{
+		ID:       "evt-1",
+		Start:    time.Now().Add(-time.Minute),
+		Duration: time.Hour,
+		Level:    3,
+	})
+
+	st.Expect(t, ven.Poll(context.Background()), nil)
+
+	w := writes(t, d)
+	st.Expect(t, w[0]["cspHome"], 26.5)
+	st.Expect(t, w[0]["OpenADReventTempMax"], 26.5)
+	st.Expect(t, w[0]["hspHome"], 17.0)
+	st.Expect(t, len(vtn.Responses()), 0)
+}
+
+func TestVENSetpointRange(t *testing.T) {
+	defer gock.Off()
+
+	device := thermostat()
+	device["OpenADRglobalTempMin"] = 0
+	device["OpenADRglobalTempMax"] = 32.2
+	device["cspHome"] = 31
+	device["hspHome"] = 11
+
+	d := mockDaikin(device)
+
+	vtn := openadr.NewLocalVTN()
+	server := httptest.NewServer(vtn)
+	defer server.Close()
+
+	ven := newVEN(d, server.URL)
+	vtn.SetEvent(openadr.Event{
+		ID:       "evt-1",
+		Start:    time.Now().Add(-time.Minute),
+		Duration: time.Hour,
+		Level:    3,
+	})
+
+	st.Expect(t, ven.Poll(context.Background()), nil)
+
+	w := writes(t, d)
+	st.Expect(t, w[0]["cspHome"], 32.0)
+	st.Expect(t, w[0]["OpenADReventTempMax"], 32.0)
+	st.Expect(t, w[0]["hspHome"], 10.0)
+}
+
+func TestVENOverride(t *testing.T) {
+	defer gock.Off()
+
+	overridden := thermostat()
+	overridden["OpenADRoverrideState"] = 1
+	overridden["cspHome"] = 23
+
+	d := mockDaikin(thermostat(), overridden)
+
+	vtn := openadr.NewLocalVTN()
+	server := httptest.NewServer(vtn)
+	defer server.Close()
+
+	ven := newVEN(d, server.URL)
+	ctx := context.Background()
+
+	vtn.SetEvent(openadr.Event{
+		ID:               "evt-1",
+		Start:            time.Now().Add(-time.Minute),
+		Duration:         time.Hour,
+		Level:            1,
+		ResponseRequired: true,
+	})
+
+	st.Expect(t, ven.Poll(ctx), nil)
+	st.Expect(t, len(writes(t, d)), 1)
+
+	st.Expect(t, ven.Poll(ctx), nil)
+
+	responses := vtn.Responses()
+	st.Expect(t, len(responses), 2)
+	st.Expect(t, responses[1].Opt, openadr.OptOut)
+
+	// the occupant's setpoints are kept once the event is gone
+	vtn.RemoveEvent("evt-1")
+	st.Expect(t, ven.Poll(ctx), nil)
+
+	w := writes(t, d)
+	st.Expect(t, len(w), 2)
+	_, ok := w[0]["cspHome"]
+	st.Expect(t, ok, false)
+	_, ok = w[1]["cspHome"]
+	st.Expect(t, ok, false)
+	st.Expect(t, w[1]["OpenADReventID"], "")
+}
+
+func TestVENBackToBackEvents(t *testing.T) {
+	defer gock.Off()
+
+	d := mockDaikin(thermostat())
+
+	vtn := openadr.NewLocalVTN()
+	server := httptest.NewServer(vtn)
+	defer server.Close()
+
+	ven := newVEN(d, server.URL)
+	ctx := context.Background()
+
+	vtn.SetEvent(openadr.Event{
+		ID:       "a",
+		Start:    time.Now().Add(-time.Minute),
+		Duration: time.Hour,
+		Level:    2,
+	})
+	vtn.SetEvent(openadr.Event{
+		ID:       "b",
+		Start:    time.Now().Add(3 * time.Hour),
+		Duration: time.Hour,
+		Level:    1,
+	})
+
+	st.Expect(t, ven.Poll(ctx), nil)
+
+	w := writes(t, d)
+	st.Expect(t, len(w), 1)
+	st.Expect(t, w[0]["OpenADReventID"], "a")
+	st.Expect(t, w[0]["cspHome"], 26.0)
+
+	// the setpoints are restored while the next event is still pending
+	vtn.RemoveEvent("a")
+	st.Expect(t, ven.Poll(ctx), nil)
+
+	w = writes(t, d)
+	st.Expect(t, len(w), 1)
+	st.Expect(t, w[0]["OpenADReventID"], "b")
+	st.Expect(t, w[0]["cspHome"], 24.0)
+	st.Expect(t, w[0]["hspHome"], 20.0)
+	st.Expect(t, w[0]["schedOverride"], 0.0)
+}
+
+func TestVENOpenEndedEvent(t *testing.T) {
+	defer gock.Off()
+
+	d := mockDaikin(thermostat())
+
+	vtn := openadr.NewLocalVTN()
+	server := httptest.NewServer(vtn)
+	defer server.Close()
+
+	ven := newVEN(d, server.URL)
+	ctx := context.Background()
+
+	start := time.Now().Add(-48 * time.Hour)
+	event := openadr.Event{ID: "evt-1", Start: start, Level: 1}
+	st.Expect(t, event.ActiveAt(time.Now()), true)
+	st.Expect(t, event.ActiveAt(start.Add(-time.Second)), false)
+
+	vtn.SetEvent(event)
+	st.Expect(t, ven.Poll(ctx), nil)
+
+	events := ven.Events()
+	st.Expect(t, events[0].Status, openadr.StatusActive)
+	st.Expect(t, events[0].Duration, time.Duration(0))
+
+	w := writes(t, d)
+	st.Expect(t, len(w), 1)
+	st.Expect(t, w[0]["OpenADReventStop"], 0.0)
+	st.Expect(t, w[0]["cspHome"], 25.0)
+
+	vtn.CancelEvent("evt-1")
+	st.Expect(t, ven.Poll(ctx), nil)
+
+	w = writes(t, d)
+	st.Expect(t, len(w), 1)
+	st.Expect(t, w[0]["cspHome"], 24.0)
+}
+
+func TestVENRunDefaultInterval(t *testing.T) {
+	ven := newVEN(daikin.New(email, password), "http://127.0.0.1:1")
+	ven.Interval = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	st.Expect(t, ven.Run(ctx), context.Canceled)
+}
+
+// a distributeEvent as sent by a third-party VTN, with the usual prefixes
+const distributeEvent = `<?xml version="1.0" encoding="UTF-8"?>
+<oadr:oadrPayload xmlns:oadr="http://openadr.org/oadr-2.0b/2012/07" xmlns:ei="http://docs.oasis-open.org/ns/energyinterop/201110" xmlns:pyld="http://docs.oasis-open.org/ns/energyinterop/201110/payloads" xmlns:xcal="urn:ietf:params:xml:ns:icalendar-2.0" xmlns:strm="urn:ietf:params:xml:ns:icalendar-2.0:stream" xmlns:emix="http://docs.oasis-open.org/ns/emix/2011/06">
+  <oadr:oadrSignedObject>
+    <oadr:oadrDistributeEvent ei:schemaVersion="2.0b">
+      <pyld:requestID>req-1</pyld:requestID>
+      <ei:vtnID>utility</ei:vtnID>
+      <oadr:oadrEvent>
+        <ei:eiEvent>
+          <ei:eventDescriptor>
+            <ei:eventID>peak-2030</ei:eventID>
+            <ei:modificationNumber>2</ei:modificationNumber>
+            <ei:priority>1</ei:priority>
+            <ei:eiMarketContext>
+              <emix:marketContext>http://utility.example.com/program</emix:marketContext>
+            </ei:eiMarketContext>
+            <ei:createdDateTime>2030-07-01T12:00:00Z</ei:createdDateTime>
+            <ei:eventStatus>far</ei:eventStatus>
+          </ei:eventDescriptor>
+          <ei:eiActivePeriod>
+            <xcal:properties>
+              <xcal:dtstart>
+                <xcal:date-time>2030-07-02T21:00:00Z</xcal:date-time>
+              </xcal:dtstart>
+              <xcal:duration>
+                <xcal:duration>PT2H30M</xcal:duration>
+              </xcal:duration>
+            </xcal:properties>
+            <xcal:components xsi:nil="true" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"/>
+          </ei:eiActivePeriod>
+          <ei:eiEventSignals>
+            <ei:eiEventSignal>
+              <strm:intervals>
+                <ei:interval>
+                  <xcal:duration><xcal:duration>PT2H30M</xcal:duration></xcal:duration>
+                  <xcal:uid><xcal:text>0</xcal:text></xcal:uid>
+                  <ei:signalPayload><ei:payloadFloat><ei:value>1.0</ei:value></ei:payloadFloat></ei:signalPayload>
+                </ei:interval>
+              </strm:intervals>
+              <ei:signalName>SIMPLE</ei:signalName>
+              <ei:signalType>level</ei:signalType>
+              <ei:signalID>sig-0</ei:signalID>
+              <ei:currentValue><ei:payloadFloat><ei:value>1.0</ei:value></ei:payloadFloat></ei:currentValue>
+            </ei:eiEventSignal>
+          </ei:eiEventSignals>
+          <ei:eiTarget/>
+        </ei:eiEvent>
+        <oadr:oadrResponseRequired>always</oadr:oadrResponseRequired>
+      </oadr:oadrEvent>
+    </oadr:oadrDistributeEvent>
+  </oadr:oadrSignedObject>
+</oadr:oadrPayload>`
+
+func TestVENThirdPartyPayload(t *testing.T) {
+	defer gock.Off()
+
+	d := mockDaikin(thermostat())
+
+	var created []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/EiEvent" {
+			created, _ = io.ReadAll(r.Body)
+			w.Write([]byte(`<oadr:oadrPayload xmlns:oadr="http://openadr.org/oadr-2.0b/2012/07" xmlns:ei="http://docs.oasis-open.org/ns/energyinterop/201110"><oadr:oadrSignedObject><oadr:oadrResponse ei:schemaVersion="2.0b"><ei:eiResponse><ei:responseCode>200</ei:responseCode></ei:eiResponse></oadr:oadrResponse></oadr:oadrSignedObject></oadr:oadrPayload>`))
+			return
+		}
+		w.Write([]byte(distributeEvent))
+	}))
+	defer server.Close()
+
+	ven := openadr.NewVEN(d, deviceId, server.URL, "ven-1")
+	ven.Client = &http.Client{Transport: &http.Transport{}}
+
+	st.Expect(t, ven.Poll(context.Background()), nil)
+
+	events := ven.Events()
+	st.Expect(t, len(events), 1)
+	st.Expect(t, events[0].ID, "peak-2030")
+	st.Expect(t, events[0].ModificationNumber, 2)
+	st.Expect(t, events[0].Status, openadr.StatusFar)
+	st.Expect(t, events[0].Start, time.Date(2030, 7, 2, 21, 0, 0, 0, time.UTC))
+	st.Expect(t, events[0].Duration, 150*time.Minute)
+	st.Expect(t, events[0].Level, 1)
+	st.Expect(t, events[0].Price == nil, true)
+
+	// upcoming events are shown on the thermostat without touching setpoints
+	w := writes(t, d)
+	st.Expect(t, len(w), 1)
+	st.Expect(t, w[0]["OpenADReventID"], "peak-2030")
+	_, ok := w[0]["cspHome"]
+	st.Expect(t, ok, false)
+
+	st.Expect(t, strings.Contains(string(created), ">optIn<"), true)
+	st.Expect(t, strings.Contains(string(created), ">peak-2030<"), true)
+}
@@ -0,0 +1,208 @@
+package openadr
+
+import (
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Response is an opt status reported by a VEN.
+type Response struct {
+	VenID              string
+	EventID            string
+	ModificationNumber int
+	Opt                OptType
+	Time               time.Time
+}
+
+// LocalVTN is a minimal stand-in VTN that hands its events to every VEN that
+// polls it and records their responses. It is meant for tests and for trying
+// out a VEN without enrolling with a utility; serve it with net/http or
+// httptest and point the VEN at its URL.
+type LocalVTN struct {
+	VtnID string
+
+	mu        sync.Mutex
+	events    map[string]Event
+	version   int
+	delivered map[string]int
+	responses []Response
+	requests  int
+}
+
+func NewLocalVTN() *LocalVTN {
+	return &LocalVTN{
+		VtnID:     "local-vtn",
+		events:    map[string]Event{},
+		delivered: map[string]int{},
+	}
+}
+
+// SetEvent adds an event or replaces the one with the same ID. The
+// modification number is increased when an event is replaced.
+func (s *LocalVTN) SetEvent(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if old, ok := s.events[e.ID]; ok && e.ModificationNumber <= old.ModificationNumber {
+		e.ModificationNumber = old.ModificationNumber + 1
+	}
+	if e.Status == "" {
+		e.Status = StatusFar
+	}
+
+	s.events[e.ID] = e
+	s.version++
+}
+
+func (s *LocalVTN) CancelEvent(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.events[id]
+	if !ok {
+		return
+	}
+
+	e.Status = StatusCancelled
+	e.ModificationNumber++
+	s.events[id] = e
+	s.version++
+}
+
+// RemoveEvent stops distributing an event. VENs treat it as cancelled.
+func (s *LocalVTN) RemoveEvent(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.events, id)
+	s.version++
+}
+
+func (s *LocalVTN) Responses() []Response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Response(nil), s.responses...)
+}
+
+func (s *LocalVTN) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "reading request failed", http.StatusBadRequest)
+		return
+	}
+
+	req, err := unmarshalPayload(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var res signedObject
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/OadrPoll") && req.Poll != nil:
+		res = s.poll(req.Poll.VenID)
+	case strings.HasSuffix(r.URL.Path, "/EiEvent") && req.CreatedEvent != nil:
+		res = s.createdEvent(req.CreatedEvent)
+	default:
+		http.Error(w, "unsupported request", http.StatusBadRequest)
+		return
+	}
+
+	out, err := marshalPayload(res)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.Write(out)
+}
+
+// poll sends the events when they changed since the VEN last received them.
+func (s *LocalVTN) poll(venId string) signedObject {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	requestID := strconv.Itoa(s.requests)
+
+	if v, ok := s.delivered[venId]; ok && v == s.version {
+		return signedObject{Response: &oadrResponse{
+			SchemaVersion: schemaVersion,
+			Response:      eiResponse{ResponseCode: responseOK, RequestID: requestID},
+			VenID:         venId,
+		}}
+	}
+	s.delivered[venId] = s.version
+
+	events := make([]Event, 0, len(s.events))
+	for _, e := range s.events {
+		events = append(events, e)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+
+	d := &oadrDistributeEvent{
+		SchemaVersion: schemaVersion,
+		RequestID:     requestID,
+		VtnID:         s.VtnID,
+	}
+	now := time.Now()
+	for _, e := range events {
+		e.Status = statusAt(e, now)
+		d.Events = append(d.Events, encodeEvent(e))
+	}
+
+	return signedObject{DistributeEvent: d}
+}
+
+func (s *LocalVTN) createdEvent(c *oadrCreatedEvent) signedObject {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, r := range c.CreatedEvent.EventResponses.Responses {
+		s.responses = append(s.responses, Response{
+			VenID:              c.CreatedEvent.VenID,
+			EventID:            r.EventID,
+			ModificationNumber: r.ModificationNumber,
+			Opt:                OptType(r.OptType),
+			Time:               now,
+		})
+	}
+
+	return signedObject{Response: &oadrResponse{
+		SchemaVersion: schemaVersion,
+		Response:      eiResponse{ResponseCode: responseOK},
+		VenID:         c.CreatedEvent.VenID,
+	}}
+}
+
+// VENs are told an event is near within this long of its start
+const nearWindow = time.Hour
+
+func statusAt(e Event, now time.Time) EventStatus {
+	switch {
+	case e.Status == StatusCancelled:
+		return StatusCancelled
+	case e.endedAt(now):
+		return StatusCompleted
+	case !now.Before(e.Start):
+		return StatusActive
+	case e.Start.Sub(now) <= nearWindow:
+		return StatusNear
+	}
+	return StatusFar
+}
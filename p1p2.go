@@ -0,0 +1,240 @@
+package daikin
+
+import (
+	"strconv"
+	"time"
+)
+
+// P1P2 fields exist once without a suffix for the first indoor unit and
+// again with a Unit1..Unit15 suffix for the others.
+const p1p2IndoorUnitCount = 16
+
+type P1P2IndoorUnit struct {
+	Unit                           int
+	Type                           *int
+	Address                        *int
+	ModelName                      *string
+	SerialNumber                   *string
+	SuctionAirThermistor           *Temperature
+	DischargeAirThermistor         *Temperature
+	HeatExchangerThermistor        *Temperature
+	HeatExchangerGasPipeThermistor *Temperature
+	FanSpeed                       *int
+	FanTap                         *bool
+	FlapSwing                      *int
+	EEVOpenPulses                  *int
+	OperatingTime                  *time.Duration
+	FanOperationTime               *time.Duration
+	EnergizedTime                  *time.Duration
+	AntiFreezeControl              *bool
+	DrainPump                      *bool
+	ElectricHeater                 *bool
+	Float                          *bool
+	Humidifier                     *bool
+	T1T2                           *bool
+}
+
+// P1P2IndoorUnits returns the indoor units that report data over P1P2,
+// ordered by unit number. Values whose Valid flag is false are left nil.
+func (di *DeviceInfo) P1P2IndoorUnits() []P1P2IndoorUnit {
+	var units []P1P2IndoorUnit
+
+	for unit := 0; unit < p1p2IndoorUnitCount; unit++ {
+		if !di.boolField(p1p2Name("P1P2IndoorUnitDataValid", unit)) {
+			continue
+		}
+
+		u := P1P2IndoorUnit{
+			Unit:                           unit,
+			Address:                        di.p1p2Int("P1P2IndoorUnitAddress", unit),
+			ModelName:                      di.p1p2String("P1P2IndoorUnitModelName", unit),
+			SerialNumber:                   di.p1p2String("P1P2IndoorUnitSerialNumberEquip", unit),
+			SuctionAirThermistor:           di.p1p2Temperature("P1P2IndoorSuctionAirThermistor", unit),
+			DischargeAirThermistor:         di.p1p2Temperature("P1P2IndoorUnitDischargeAirThermistor", unit),
+			HeatExchangerThermistor:        di.p1p2Temperature("P1P2IndoorUnitHeatExchangerThermistor", unit),
+			HeatExchangerGasPipeThermistor: di.p1p2Temperature("P1P2IndoorUnitHeatExchangerGasPipeThermistor", unit),
+			FanSpeed:                       di.p1p2Int("P1P2IndoorUnitFanSpeed", unit),
+			FanTap:                         di.p1p2Bool("P1P2IndoorUnitFanTap", unit),
+			FlapSwing:                      di.p1p2Int("P1P2IndoorUnitFlapSwing", unit),
+			EEVOpenPulses:                  di.p1p2Int("P1P2IndoorUnitEEVOpenPulses", unit),
+			OperatingTime:                  di.p1p2Hours("P1P2IndoorUnitOperatingTime", unit),
+			FanOperationTime:               di.p1p2Hours("P1P2IndoorUnitFanOperationTime", unit),
+			EnergizedTime:                  di.p1p2Hours("P1P2IndoorUnitEnergizedTime", unit),
+			AntiFreezeControl:              di.p1p2Bool("P1P2AntiFreezeControlOnOff", unit),
+			DrainPump:                      di.p1p2Bool("P1P2DrainPumpOnOff", unit),
+			ElectricHeater:                 di.p1p2Bool("P1P2ElectricHeaterOnOff", unit),
+			Float:                          di.p1p2Bool("P1P2FloatOnOff", unit),
+			Humidifier:                     di.p1p2Bool("P1P2HumidifierOnOff", unit),
+			T1T2:                           di.p1p2Bool("P1P2T1T2OnOff", unit),
+		}
+
+		// unit type has no Valid flag and is 255 when unset
+		name := p1p2Name("P1P2IndoorUnitType", unit)
+		if di.field(name).IsValid() && di.intField(name) != ctByteNotAvailable {
+			unitType := di.intField(name)
+			u.Type = &unitType
+		}
+
+		units = append(units, u)
+	}
+
+	return units
+}
+
+func p1p2Name(base string, unit int) string {
+	if unit == 0 {
+		return base
+	}
+	return base + "Unit" + strconv.Itoa(unit)
+}
+
+func (di *DeviceInfo) p1p2Valid(base string, unit int) (string, bool) {
+	name := p1p2Name(base, unit)
+	return name, di.boolField(name + "Valid")
+}
+
+func (di *DeviceInfo) p1p2Int(base string, unit int) *int {
+	name, ok := di.p1p2Valid(base, unit)
+	if !ok {
+		return nil
+	}
+	v := di.intField(name)
+	return &v
+}
+
+func (di *DeviceInfo) p1p2Bool(base string, unit int) *bool {
+	name, ok := di.p1p2Valid(base, unit)
+	if !ok {
+		return nil
+	}
+	v := di.boolField(name)
+	return &v
+}
+
+func (di *DeviceInfo) p1p2String(base string, unit int) *string {
+	name, ok := di.p1p2Valid(base, unit)
+	if !ok {
+		return nil
+	}
+	v := ctString(di.stringField(name))
+	return &v
+}
+
+// P1P2 thermistors report whole degrees celsius
+func (di *DeviceInfo) p1p2Temperature(base string, unit int) *Temperature {
+	v := di.p1p2Int(base, unit)
+	if v == nil {
+		return nil
+	}
+	t := Celsius(float32(*v))
+	return &t
+}
+
+func (di *DeviceInfo) p1p2Hours(base string, unit int) *time.Duration {
+	v := di.p1p2Int(base, unit)
+	if v == nil {
+		return nil
+	}
+	d := time.Duration(*v) * time.Hour
+	return &d
+}
+
+type P1P2Outdoor struct {
+	Address                        *int
+	ModelName                      *string
+	SerialNumber                   *string
+	AirThermistor                  *Temperature
+	DischargePipeThermistor        *Temperature
+	LiquidPipeThermistor           *Temperature
+	HeatExchangerThermistor        *Temperature
+	HeatExchangerGasPipeThermistor *Temperature
+	HeatExchangerDeicerThermistor  *Temperature
+	EEVOpenPulses                  *int
+	FanTap                         *int
+	OperatingTime                  *time.Duration
+	Compressor1OperationTime       *time.Duration
+	Compressor2OperationTime       *time.Duration
+	Fan1OperationTime              *time.Duration
+	Fan2OperationTime              *time.Duration
+	TestOperationActive            bool
+}
+
+// P1P2Outdoor returns nil when the outdoor unit does not report data over P1P2.
+func (di *DeviceInfo) P1P2Outdoor() *P1P2Outdoor {
+	if !di.P1P2OutdoorUnitDataValid {
+		return nil
+	}
+
+	return &P1P2Outdoor{
+		Address:                        di.p1p2Int("P1P2OutdoorUnitAddress", 0),
+		ModelName:                      di.p1p2String("P1P2OutdoorUnitModelName", 0),
+		SerialNumber:                   di.p1p2String("P1P2OutdoorUnitSerialNumberEquip", 0),
+		AirThermistor:                  di.p1p2Temperature("P1P2OutdoorAirThermistor", 0),
+		DischargePipeThermistor:        di.p1p2Temperature("P1P2OutdoorUnitDischargePipeThermistor", 0),
+		LiquidPipeThermistor:           di.p1p2Temperature("P1P2OutdoorUnitLiquidPipeThermistor", 0),
+		HeatExchangerThermistor:        di.p1p2Temperature("P1P2OutdoorUnitHeatExchangerThermistor", 0),
+		HeatExchangerGasPipeThermistor: di.p1p2Temperature("P1P2OutdoorUnitHeatExchangerGasPipeThermistor", 0),
+		HeatExchangerDeicerThermistor:  di.p1p2Temperature("P1P2OutdoorUnitHeatExchangerDeicerThermistor", 0),
+		EEVOpenPulses:                  di.p1p2Int("P1P2OutdoorUnitEEVOpenPulses", 0),
+		FanTap:                         di.p1p2Int("P1P2OutdoorUnitFanTap", 0),
+		OperatingTime:                  di.p1p2Hours("P1P2OutdoorUnitOperatingTime", 0),
+		Compressor1OperationTime:       di.p1p2Hours("P1P2OutdoorUnitCompressor1OperationTime", 0),
+		Compressor2OperationTime:       di.p1p2Hours("P1P2OutdoorUnitCompressor2OperationTime", 0),
+		Fan1OperationTime:              di.p1p2Hours("P1P2OutdoorUnitFan1OperationTime", 0),
+		Fan2OperationTime:              di.p1p2Hours("P1P2OutdoorUnitFan2OperationTime", 0),
+		TestOperationActive:            di.P1P2OutdoorUnitTestOperationActive,
+	}
+}
+
+type FieldSettingGroup uint8
+
+const (
+	FieldSettingPublic FieldSettingGroup = iota
+	FieldSettingNonPublic
+	FieldSettingSuperSecret
+)
+
+var fieldSettingPrefixes = map[FieldSettingGroup]string{
+	FieldSettingPublic:      "P1P2",
+	FieldSettingNonPublic:   "P1P2NonPublic",
+	FieldSettingSuperSecret: "P1P2SuperSecret",
+}
+
+const (
+	fieldSettingSwitches = 16
+	fieldSettingModeMax  = 255
+	fieldSettingUnitMax  = p1p2IndoorUnitCount - 1
+)
+
+type FieldSettingKey struct {
+	Group  FieldSettingGroup
+	Mode   int
+	Switch int
+}
+
+// FieldSetting holds the value last received from the equipment and the
+// value last sent to it for one switch.
+type FieldSetting struct {
+	Received int
+	Sent     int
+}
+
+// P1P2FieldSettings decodes the SW0..SWf registers of each group. The
+// registers hold the switches of the mode currently selected for the group.
+func (di *DeviceInfo) P1P2FieldSettings() map[FieldSettingKey]FieldSetting {
+	settings := map[FieldSettingKey]FieldSetting{}
+
+	for group, prefix := range fieldSettingPrefixes {
+		mode := di.intField(prefix + "FieldSettingModeNumber")
+
+		for sw := 0; sw < fieldSettingSwitches; sw++ {
+			suffix := "FieldSettingSW" + strconv.FormatInt(int64(sw), 16)
+			settings[FieldSettingKey{Group: group, Mode: mode, Switch: sw}] = FieldSetting{
+				Received: di.intField(prefix + "Received" + suffix),
+				Sent:     di.intField(prefix + "Sent" + suffix),
+			}
+		}
+	}
+
+	return settings
+}
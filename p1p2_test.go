@@ -0,0 +1,104 @@
+package daikin_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nbio/st"
+	"github.com/redgoose/daikin-skyport"
+)
+
+func TestP1P2IndoorUnitsFixture(t *testing.T) {
+	deviceInfo := loadDeviceInfo(t)
+
+	st.Expect(t, len(deviceInfo.P1P2IndoorUnits()), 0)
+}
+
+func TestP1P2IndoorUnits(t *testing.T) {
+	deviceInfo := &daikin.DeviceInfo{
+		P1P2IndoorUnitDataValid:               true,
+		P1P2IndoorSuctionAirThermistor:        23,
+		P1P2IndoorSuctionAirThermistorValid:   true,
+		P1P2IndoorUnitFanSpeed:                3,
+		P1P2IndoorUnitFanSpeedValid:           false,
+		P1P2IndoorUnitDataValidUnit3:          true,
+		P1P2IndoorUnitTypeUnit3:               12,
+		P1P2IndoorUnitOperatingTimeUnit3:      1500,
+		P1P2IndoorUnitOperatingTimeUnit3Valid: true,
+		P1P2DrainPumpOnOffUnit3:               true,
+		P1P2DrainPumpOnOffUnit3Valid:          true,
+		P1P2IndoorUnitModelNameUnit3:          "FTXS12LVJU     ",
+		P1P2IndoorUnitModelNameUnit3Valid:     true,
+		P1P2IndoorUnitEEVOpenPulsesUnit4:      200,
+		P1P2IndoorUnitEEVOpenPulsesUnit4Valid: true,
+	}
+
+	units := deviceInfo.P1P2IndoorUnits()
+	st.Expect(t, len(units), 2)
+
+	st.Expect(t, units[0].Unit, 0)
+	st.Expect(t, *units[0].SuctionAirThermistor, daikin.Celsius(23))
+	st.Expect(t, units[0].FanSpeed == nil, true)
+	st.Expect(t, units[0].Type == nil, true)
+
+	st.Expect(t, units[1].Unit, 3)
+	st.Expect(t, *units[1].Type, 12)
+	st.Expect(t, *units[1].OperatingTime, 1500*time.Hour)
+	st.Expect(t, *units[1].DrainPump, true)
+	st.Expect(t, *units[1].ModelName, "FTXS12LVJU")
+	st.Expect(t, units[1].EEVOpenPulses == nil, true)
+}
+
+func TestP1P2OutdoorFixture(t *testing.T) {
+	deviceInfo := loadDeviceInfo(t)
+
+	st.Expect(t, deviceInfo.P1P2Outdoor() == nil, true)
+}
+
+func TestP1P2Outdoor(t *testing.T) {
+	deviceInfo := &daikin.DeviceInfo{
+		P1P2OutdoorUnitDataValid:                     true,
+		P1P2OutdoorUnitCompressor1OperationTime:      4200,
+		P1P2OutdoorUnitCompressor1OperationTimeValid: true,
+		P1P2OutdoorUnitCompressor2OperationTime:      10,
+		P1P2OutdoorUnitDischargePipeThermistor:       71,
+		P1P2OutdoorUnitDischargePipeThermistorValid:  true,
+		P1P2OutdoorUnitEEVOpenPulses:                 320,
+		P1P2OutdoorUnitEEVOpenPulsesValid:            true,
+		P1P2OutdoorAirThermistor:                     -4,
+		P1P2OutdoorAirThermistorValid:                true,
+	}
+
+	outdoor := deviceInfo.P1P2Outdoor()
+	st.Expect(t, *outdoor.Compressor1OperationTime, 4200*time.Hour)
+	st.Expect(t, outdoor.Compressor2OperationTime == nil, true)
+	st.Expect(t, *outdoor.DischargePipeThermistor, daikin.Celsius(71))
+	st.Expect(t, *outdoor.EEVOpenPulses, 320)
+	st.Expect(t, *outdoor.AirThermistor, daikin.Celsius(-4))
+	st.Expect(t, outdoor.ModelName == nil, true)
+}
+
+func TestP1P2FieldSettings(t *testing.T) {
+	deviceInfo := &daikin.DeviceInfo{
+		P1P2NonPublicFieldSettingModeNumber:  21,
+		P1P2NonPublicReceivedFieldSettingSW2: 3,
+		P1P2NonPublicSentFieldSettingSW2:     4,
+		P1P2NonPublicReceivedFieldSettingSWf: 7,
+	}
+
+	settings := deviceInfo.P1P2FieldSettings()
+	st.Expect(t, len(settings), 48)
+
+	sw2 := settings[daikin.FieldSettingKey{Group: daikin.FieldSettingNonPublic, Mode: 21, Switch: 2}]
+	st.Expect(t, sw2, daikin.FieldSetting{Received: 3, Sent: 4})
+
+	swf := settings[daikin.FieldSettingKey{Group: daikin.FieldSettingNonPublic, Mode: 21, Switch: 15}]
+	st.Expect(t, swf.Received, 7)
+}
+
+func TestP1P2FieldSettingsFixture(t *testing.T) {
+	deviceInfo := loadDeviceInfo(t)
+
+	settings := deviceInfo.P1P2FieldSettings()
+	st.Expect(t, settings[daikin.FieldSettingKey{Group: daikin.FieldSettingPublic, Mode: 0, Switch: 10}], daikin.FieldSetting{Received: 15, Sent: 15})
+}
@@ -0,0 +1,238 @@
+package daikin
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	defaultRuntimeMaxGap = 15 * time.Minute
+
+	// reported by boards that do not track runtime
+	runtimeNotAvailable = 0xFFFFFFFF
+)
+
+type DailyRuntime struct {
+	Date             time.Time
+	Heat             time.Duration
+	Cool             time.Duration
+	Overcool         time.Duration
+	Fan              time.Duration
+	Idle             time.Duration
+	CompressorCycles int
+	ShortCycles      int
+}
+
+// ShortCycle is a compressor run or rest shorter than the configured
+// CompressorMinOn or CompressorMinOff.
+type ShortCycle struct {
+	Start    time.Time
+	Duration time.Duration
+	Minimum  time.Duration
+	Running  bool
+}
+
+type RuntimeCrossCheck struct {
+	TrackedCompressor   time.Duration
+	ReportedCompressor  time.Duration
+	CompressorAvailable bool
+	TrackedFan          time.Duration
+	ReportedFan         time.Duration
+	FanAvailable        bool
+}
+
+// RuntimeTracker accumulates equipment runtime from successive DeviceInfo
+// samples. Time between two samples is attributed to the status of the first
+// one; gaps longer than MaxGap are skipped. The zero value is ready to use.
+type RuntimeTracker struct {
+	MaxGap   time.Duration
+	Location *time.Location
+
+	days        map[time.Time]*DailyRuntime
+	shortCycles []ShortCycle
+
+	first      *DeviceInfo
+	last       *DeviceInfo
+	lastAt     time.Time
+	compressor bool
+	changedAt  time.Time
+
+	trackedCompressor time.Duration
+	trackedFan        time.Duration
+}
+
+func NewRuntimeTracker() *RuntimeTracker {
+	return &RuntimeTracker{MaxGap: defaultRuntimeMaxGap}
+}
+
+func (t *RuntimeTracker) Add(at time.Time, deviceInfo *DeviceInfo) {
+	if t.days == nil {
+		t.days = map[time.Time]*DailyRuntime{}
+	}
+
+	if t.MaxGap <= 0 {
+		t.MaxGap = defaultRuntimeMaxGap
+	}
+
+	if t.Location == nil {
+		t.Location = deviceInfo.Location()
+	}
+
+	if t.last == nil {
+		t.first = deviceInfo
+		t.last = deviceInfo
+		t.lastAt = at
+		t.compressor = compressorRunning(deviceInfo)
+		return
+	}
+
+	if !at.After(t.lastAt) {
+		return
+	}
+
+	if at.Sub(t.lastAt) <= t.MaxGap {
+		t.accumulate(t.lastAt, at, t.last.EquipmentStatus)
+	}
+
+	running := compressorRunning(deviceInfo)
+	if running != t.compressor {
+		t.transition(at, running, deviceInfo)
+	}
+
+	t.last = deviceInfo
+	t.lastAt = at
+}
+
+func (t *RuntimeTracker) transition(at time.Time, running bool, deviceInfo *DeviceInfo) {
+	day := t.day(at)
+
+	if running {
+		day.CompressorCycles++
+	}
+
+	// the first transition has no known start, so it can't be measured
+	if !t.changedAt.IsZero() {
+		minimum := time.Duration(deviceInfo.CompressorMinOff) * time.Millisecond
+		if t.compressor {
+			minimum = time.Duration(deviceInfo.CompressorMinOn) * time.Millisecond
+		}
+
+		duration := at.Sub(t.changedAt)
+		if duration < minimum {
+			day.ShortCycles++
+			t.shortCycles = append(t.shortCycles, ShortCycle{
+				Start:    t.changedAt,
+				Duration: duration,
+				Minimum:  minimum,
+				Running:  t.compressor,
+			})
+		}
+	}
+
+	t.compressor = running
+	t.changedAt = at
+}
+
+func (t *RuntimeTracker) accumulate(from time.Time, to time.Time, status EquipmentStatus) {
+	for from.Before(to) {
+		start := t.startOfDay(from)
+		end := start.AddDate(0, 0, 1)
+		if end.After(to) {
+			end = to
+		}
+
+		elapsed := end.Sub(from)
+		day := t.day(from)
+
+		switch status {
+		case EquipmentStatusHeat:
+			day.Heat += elapsed
+		case EquipmentStatusCool:
+			day.Cool += elapsed
+		case EquipmentStatusOvercool:
+			day.Overcool += elapsed
+		case EquipmentStatusFan:
+			day.Fan += elapsed
+		default:
+			day.Idle += elapsed
+		}
+
+		if status != EquipmentStatusIdle && status != 0 {
+			t.trackedFan += elapsed
+		}
+
+		if t.compressor {
+			t.trackedCompressor += elapsed
+		}
+
+		from = end
+	}
+}
+
+func (t *RuntimeTracker) startOfDay(at time.Time) time.Time {
+	at = at.In(t.Location)
+	return time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, t.Location)
+}
+
+func (t *RuntimeTracker) day(at time.Time) *DailyRuntime {
+	date := t.startOfDay(at)
+
+	day, ok := t.days[date]
+	if !ok {
+		day = &DailyRuntime{Date: date}
+		t.days[date] = day
+	}
+	return day
+}
+
+func (t *RuntimeTracker) Days() []DailyRuntime {
+	days := make([]DailyRuntime, 0, len(t.days))
+	for _, day := range t.days {
+		days = append(days, *day)
+	}
+
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date.Before(days[j].Date)
+	})
+
+	return days
+}
+
+func (t *RuntimeTracker) ShortCycles() []ShortCycle {
+	return append([]ShortCycle(nil), t.shortCycles...)
+}
+
+// CrossCheck compares the tracked runtime against the hour counters reported
+// by the outdoor unit and air handler over the same period.
+func (t *RuntimeTracker) CrossCheck() RuntimeCrossCheck {
+	check := RuntimeCrossCheck{
+		TrackedCompressor: t.trackedCompressor,
+		TrackedFan:        t.trackedFan,
+	}
+
+	if t.first == nil {
+		return check
+	}
+
+	if int64(t.first.CtOutdoorCompressorRunTime) != runtimeNotAvailable && int64(t.last.CtOutdoorCompressorRunTime) != runtimeNotAvailable {
+		check.CompressorAvailable = true
+		check.ReportedCompressor = time.Duration(t.last.CtOutdoorCompressorRunTime-t.first.CtOutdoorCompressorRunTime) * time.Hour
+	}
+
+	if t.first.CtAHFanRuntime != runtimeNotAvailable && t.last.CtAHFanRuntime != runtimeNotAvailable {
+		check.FanAvailable = true
+		check.ReportedFan = time.Duration(t.last.CtAHFanRuntime-t.first.CtAHFanRuntime) * time.Hour
+	}
+
+	return check
+}
+
+func compressorRunning(deviceInfo *DeviceInfo) bool {
+	switch deviceInfo.EquipmentStatus {
+	case EquipmentStatusCool, EquipmentStatusOvercool:
+		return true
+	case EquipmentStatusHeat:
+		return deviceInfo.CtSystemCapCompressorHeat
+	}
+	return false
+}
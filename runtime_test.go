@@ -0,0 +1,83 @@
+package daikin_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nbio/st"
+	"github.com/redgoose/daikin-skyport"
+)
+
+func runtimeSample(status daikin.EquipmentStatus, compressorHours int) *daikin.DeviceInfo {
+	return &daikin.DeviceInfo{
+		TimeZone:                   "UTC",
+		EquipmentStatus:            status,
+		CompressorMinOn:            600000,
+		CompressorMinOff:           600000,
+		CtOutdoorCompressorRunTime: compressorHours,
+		CtAHFanRuntime:             4294967295,
+	}
+}
+
+func TestRuntimeTracker(t *testing.T) {
+	start := time.Date(2023, 9, 1, 23, 0, 0, 0, time.UTC)
+	tracker := daikin.NewRuntimeTracker()
+	tracker.MaxGap = time.Hour
+
+	tracker.Add(start, runtimeSample(daikin.EquipmentStatusIdle, 100))
+	tracker.Add(start.Add(30*time.Minute), runtimeSample(daikin.EquipmentStatusCool, 100))
+	tracker.Add(start.Add(90*time.Minute), runtimeSample(daikin.EquipmentStatusIdle, 101))
+	tracker.Add(start.Add(95*time.Minute), runtimeSample(daikin.EquipmentStatusCool, 101))
+	tracker.Add(start.Add(100*time.Minute), runtimeSample(daikin.EquipmentStatusFan, 101))
+
+	days := tracker.Days()
+	st.Expect(t, len(days), 2)
+
+	st.Expect(t, days[0].Date, time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC))
+	st.Expect(t, days[0].Idle, 30*time.Minute)
+	st.Expect(t, days[0].Cool, 30*time.Minute)
+	st.Expect(t, days[0].CompressorCycles, 1)
+
+	st.Expect(t, days[1].Cool, 35*time.Minute)
+	st.Expect(t, days[1].Idle, 5*time.Minute)
+	st.Expect(t, days[1].CompressorCycles, 1)
+	st.Expect(t, days[1].ShortCycles, 2)
+
+	shortCycles := tracker.ShortCycles()
+	st.Expect(t, len(shortCycles), 2)
+	st.Expect(t, shortCycles[0].Running, false)
+	st.Expect(t, shortCycles[0].Duration, 5*time.Minute)
+	st.Expect(t, shortCycles[1].Running, true)
+	st.Expect(t, shortCycles[1].Minimum, 10*time.Minute)
+
+	check := tracker.CrossCheck()
+	st.Expect(t, check.TrackedCompressor, 65*time.Minute)
+	st.Expect(t, check.CompressorAvailable, true)
+	st.Expect(t, check.ReportedCompressor, time.Hour)
+	st.Expect(t, check.FanAvailable, false)
+}
+
+func TestRuntimeTrackerSkipsGaps(t *testing.T) {
+	start := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+	tracker := daikin.NewRuntimeTracker()
+
+	tracker.Add(start, runtimeSample(daikin.EquipmentStatusHeat, 0))
+	tracker.Add(start.Add(2*time.Hour), runtimeSample(daikin.EquipmentStatusHeat, 0))
+
+	days := tracker.Days()
+	st.Expect(t, len(days), 0)
+}
+
+func TestRuntimeTrackerZeroValue(t *testing.T) {
+	start := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+	var tracker daikin.RuntimeTracker
+
+	tracker.Add(start, runtimeSample(daikin.EquipmentStatusCool, 100))
+	tracker.Add(start.Add(10*time.Minute), runtimeSample(daikin.EquipmentStatusIdle, 100))
+
+	st.Expect(t, tracker.MaxGap, 15*time.Minute)
+
+	days := tracker.Days()
+	st.Expect(t, len(days), 1)
+	st.Expect(t, days[0].Cool, 10*time.Minute)
+}
@@ -0,0 +1,113 @@
+package daikin
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+const (
+	s21DemandLevelMin = 40
+	s21DemandLevelMax = 100
+)
+
+// S21Telemetry holds readings from a ductless unit connected over S21.
+// Readings the unit flags as invalid are nil.
+type S21Telemetry struct {
+	IndoorSuctionTemp        *Temperature
+	DischargePipeTemp        *Temperature
+	OutdoorAirTemp           *Temperature
+	OutdoorHeatExchangerTemp *Temperature
+	OutdoorLiquidPipeTemp    *Temperature
+	OutdoorFanRPM            *int
+	EEVAperture              *int
+	ElectricCurrentFlow      *int
+	PowerConsumption         *int // watts
+	ComfortModeExists        bool
+	ComfortModeEnabled       bool
+	DemandSettingEnabled     bool
+	DemandLevel              int // percent of rated capacity
+}
+
+func (di *DeviceInfo) S21Telemetry() S21Telemetry {
+	return S21Telemetry{
+		IndoorSuctionTemp:        s21Temperature(di.S21IndoorSuctionTemp, di.S21IndoorTempValid),
+		DischargePipeTemp:        s21Temperature(di.S21DischargePipeTemp, di.S21DischargePipeTempValid),
+		OutdoorAirTemp:           s21Temperature(di.S21OutdoorAirTemp, di.S21OutdoorAirTempValid),
+		OutdoorHeatExchangerTemp: s21Temperature(di.S21OutdoorHeatExchangerTemp, di.S21OutdoorHeatExchangerTempValid),
+		OutdoorLiquidPipeTemp:    s21Temperature(di.S21OutdoorLiquidPipeTemp, di.S21OutdoorLiquidPipeTempValid),
+		OutdoorFanRPM:            s21Int(di.S21OutdoorFanRPM, di.S21OutdoorFanRPMValid),
+		EEVAperture:              s21Int(di.S21EEVaperture, di.S21EEVapertureValid),
+		ElectricCurrentFlow:      s21Int(di.S21ElectricCurrentFlow, di.S21ElectricCurrentFlowValid),
+		PowerConsumption:         s21Int(di.S21PowerConsumption, di.S21PowerConsumptionValid),
+		ComfortModeExists:        di.S21ComfortModeExists,
+		ComfortModeEnabled:       di.S21ComfortModeEnable,
+		DemandSettingEnabled:     di.S21DemandSettingEnable,
+		DemandLevel:              di.S21DemandLevelSetting,
+	}
+}
+
+func s21Temperature(v float32, valid bool) *Temperature {
+	if !valid {
+		return nil
+	}
+	t := Celsius(v)
+	return &t
+}
+
+func s21Int(v int, valid bool) *int {
+	if !valid {
+		return nil
+	}
+	return &v
+}
+
+func (d *Daikin) SetS21ComfortMode(deviceId string, enabled bool) error {
+	deviceInfo, err := d.GetDeviceInfo(deviceId)
+	if err != nil {
+		return errors.New("get device info failed")
+	}
+
+	if !deviceInfo.Capabilities().ComfortMode {
+		return errors.New("comfort mode is not available on this device")
+	}
+
+	data := map[string]interface{}{
+		"S21ComfortModeEnable": enabled,
+	}
+
+	json, err := json.Marshal(data)
+	if err != nil {
+		return errors.New("json marshal failed")
+	}
+
+	return d.updateDevice(deviceId, json)
+}
+
+// SetS21DemandLevel limits the unit to level percent of its rated capacity.
+// The level is kept on the device when demand control is disabled.
+func (d *Daikin) SetS21DemandLevel(deviceId string, enabled bool, level int) error {
+	if level < s21DemandLevelMin || level > s21DemandLevelMax {
+		return errors.New("demand level outside of allowable range")
+	}
+
+	deviceInfo, err := d.GetDeviceInfo(deviceId)
+	if err != nil {
+		return errors.New("get device info failed")
+	}
+
+	if !deviceInfo.Capabilities().S21DemandControl {
+		return errors.New("demand control is not available on this device")
+	}
+
+	data := map[string]interface{}{
+		"S21DemandSettingEnable": enabled,
+		"S21DemandLevelSetting":  level,
+	}
+
+	json, err := json.Marshal(data)
+	if err != nil {
+		return errors.New("json marshal failed")
+	}
+
+	return d.updateDevice(deviceId, json)
+}
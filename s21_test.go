@@ -0,0 +1,155 @@
+package daikin_test
+
+import (
+	"errors"
+	"path"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/nbio/st"
+	"github.com/redgoose/daikin-skyport"
+)
+
+func TestS21TelemetryFixture(t *testing.T) {
+	deviceInfo := loadDeviceInfo(t)
+
+	telemetry := deviceInfo.S21Telemetry()
+	st.Expect(t, telemetry.DischargePipeTemp == nil, true)
+	st.Expect(t, telemetry.OutdoorFanRPM == nil, true)
+	st.Expect(t, telemetry.PowerConsumption == nil, true)
+	st.Expect(t, telemetry.DemandLevel, 40)
+}
+
+func TestS21Telemetry(t *testing.T) {
+	deviceInfo := &daikin.DeviceInfo{
+		S21DischargePipeTemp:      68,
+		S21DischargePipeTempValid: true,
+		S21OutdoorAirTemp:         -3.5,
+		S21OutdoorAirTempValid:    true,
+		S21OutdoorLiquidPipeTemp:  12,
+		S21OutdoorFanRPM:          840,
+		S21OutdoorFanRPMValid:     true,
+		S21PowerConsumption:       1250,
+		S21PowerConsumptionValid:  true,
+		S21ComfortModeEnable:      true,
+	}
+
+	telemetry := deviceInfo.S21Telemetry()
+	st.Expect(t, *telemetry.DischargePipeTemp, daikin.Celsius(68))
+	st.Expect(t, *telemetry.OutdoorAirTemp, daikin.Celsius(-3.5))
+	st.Expect(t, telemetry.OutdoorLiquidPipeTemp == nil, true)
+	st.Expect(t, *telemetry.OutdoorFanRPM, 840)
+	st.Expect(t, telemetry.EEVAperture == nil, true)
+	st.Expect(t, *telemetry.PowerConsumption, 1250)
+	st.Expect(t, telemetry.ComfortModeEnabled, true)
+}
+
+func TestSetS21ComfortMode(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		JSON(map[string]interface{}{"S21ComfortModeExists": true})
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{"S21ComfortModeEnable": true}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	d := daikin.New(email, password)
+
+	err := d.SetS21ComfortMode(deviceId, true)
+	st.Expect(t, err, errors.New("comfort mode is not available on this device"))
+
+	err = d.SetS21ComfortMode(deviceId, true)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestSetS21DemandLevel(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		JSON(map[string]interface{}{"S21IndoorUnitType": 1})
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{"S21DemandSettingEnable": true, "S21DemandLevelSetting": 70}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	d := daikin.New(email, password)
+
+	err := d.SetS21DemandLevel(deviceId, true, 30)
+	st.Expect(t, err, errors.New("demand level outside of allowable range"))
+
+	err = d.SetS21DemandLevel(deviceId, true, 70)
+	st.Expect(t, err, nil)
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestSetS21DemandLevelUnsupported(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	d := daikin.New(email, password)
+
+	err := d.SetS21DemandLevel(deviceId, true, 70)
+	st.Expect(t, err, errors.New("demand control is not available on this device"))
+
+	st.Expect(t, gock.IsDone(), true)
+}
@@ -0,0 +1,275 @@
+package daikin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ctSysTest* values. Tests the equipment does not support report 255.
+const (
+	sysTestIdle    = 1
+	sysTestRunning = 2
+)
+
+// demands are percentages; larger values such as 200 and 255 mean the demand
+// is not available
+const ctDemandMax = 100
+
+const (
+	defaultSystemTestDuration = 5 * time.Minute
+	maxSystemTestDuration     = 30 * time.Minute
+	defaultSystemTestInterval = 10 * time.Second
+	systemTestStopTimeout     = 30 * time.Second
+)
+
+type systemTestSpec struct {
+	field    string
+	key      string
+	demand   string
+	stage    string
+	statuses []EquipmentStatus
+}
+
+// Gas and heat pump heat share the heat demand and status, so their tests
+// also check the heat demand requested from the furnace or the outdoor unit.
+// The ventilator has no demand of its own and no equipment status, so its
+// test only checks that the device accepted and ended it.
+var systemTestSpecs = map[SystemTest]systemTestSpec{
+	SystemTestCool:         {"CtSysTestCool", "ctSysTestCool", "CtControlAlgorithmCoolDemand", "", []EquipmentStatus{EquipmentStatusCool, EquipmentStatusOvercool}},
+	SystemTestFan:          {"CtSysTestFan", "ctSysTestFan", "CtControlAlgorithmFanDemand", "", []EquipmentStatus{EquipmentStatusFan}},
+	SystemTestGasHeat:      {"CtSysTestGasHeat", "ctSysTestGasHeat", "CtControlAlgorithmHeatDemand", "CtIFCHeatRequestedDemandPercent", []EquipmentStatus{EquipmentStatusHeat}},
+	SystemTestElectricHeat: {"CtSysTestElectricHeat", "ctSysTestElectricHeat", "CtControlAlgorithmBackupHeatDemand", "CtAHHeatRequestedDemand", []EquipmentStatus{EquipmentStatusHeat}},
+	SystemTestHeatPumpHeat: {"CtSysTestHeatPumpHeat", "ctSysTestHeatPumpHeat", "CtControlAlgorithmHeatDemand", "CtOutdoorHeatRequestedDemand", []EquipmentStatus{EquipmentStatusHeat}},
+	SystemTestAuxHeater:    {"CtSysTestAuxHeater", "ctSysTestAuxHeater", "CtControlAlgorithmAuxHeatDemand", "", []EquipmentStatus{EquipmentStatusHeat}},
+	SystemTestHumidifier:   {"CtSysTestHumidifier", "ctSysTestHumidifier", "CtControlAlgorithmHumDemand", "", nil},
+	SystemTestDehumidifier: {"CtSysTestDehumidifier", "ctSysTestDehumidifier", "CtControlAlgorithmDehumDemand", "", nil},
+	SystemTestVentilator:   {"CtSysTestVentilator", "ctSysTestVentilator", "", "", nil},
+}
+
+func (s systemTestSpec) hasStatus(status EquipmentStatus) bool {
+	for _, v := range s.statuses {
+		if v == status {
+			return true
+		}
+	}
+	return false
+}
+
+// SystemTestOptions controls a system test. Zero Duration and Interval fall
+// back to the defaults. Temperature and Humidity, when set, put the
+// thermostat in temperature/humidity test mode for the length of the test.
+type SystemTestOptions struct {
+	Duration    time.Duration
+	Interval    time.Duration
+	Temperature *Temperature
+	Humidity    *int
+}
+
+// SystemTestReport is the outcome of a system test. Samples counts the device
+// readings taken while the test ran. DemandSeen, StageSeen and StatusSeen are
+// only set by a change from the readings taken before the test started.
+// Ended is false when the request to end the test failed, in which case the
+// equipment may still be running it.
+type SystemTestReport struct {
+	Test       SystemTest
+	Start      time.Time
+	End        time.Time
+	Samples    int
+	DemandSeen bool
+	MaxDemand  int
+	StageSeen  bool
+	StatusSeen bool
+	Ended      bool
+	Passed     bool
+	Failures   []string
+}
+
+// RunSystemTest runs a commissioning test of a single piece of equipment. It
+// requires InstallerMode, refuses to start while another test is running or
+// the equipment already reports the status the test looks for, and always ends the test before returning, including when ctx is cancelled.
+// A test passes when the matching control algorithm demand rose and the
+// equipment status changed to the matching one while it ran, compared to the
+// device before the test started. Heat tests also need the demand requested
+// from the equipment under test to rise.
+func (d *Daikin) RunSystemTest(ctx context.Context, deviceId string, test SystemTest, opts SystemTestOptions) (report *SystemTestReport, err error) {
+	if !d.InstallerMode {
+		return nil, errors.New("installer mode is required to run system tests")
+	}
+
+	spec, ok := systemTestSpecs[test]
+	if !ok {
+		return nil, errors.New("invalid system test")
+	}
+
+	if opts.Duration == 0 {
+		opts.Duration = defaultSystemTestDuration
+	}
+	if opts.Duration < 0 || opts.Duration > maxSystemTestDuration {
+		return nil, errors.New("system test duration outside of allowable range")
+	}
+
+	if opts.Interval <= 0 {
+		opts.Interval = defaultSystemTestInterval
+	}
+
+	if opts.Humidity != nil && (*opts.Humidity < 0 || *opts.Humidity > 100) {
+		return nil, errors.New("test mode humidity outside of allowable range")
+	}
+
+	deviceInfo, err := d.getDeviceInfo(ctx, deviceId)
+	if err != nil {
+		return nil, errors.New("get device info failed")
+	}
+
+	for _, other := range systemTestSpecs {
+		if deviceInfo.intField(other.field) == sysTestRunning {
+			return nil, errors.New("a system test is already running")
+		}
+	}
+
+	if deviceInfo.TempHumTestMode {
+		return nil, errors.New("a system test is already running")
+	}
+
+	if !deviceInfo.Capabilities().SupportsSystemTest(test) || deviceInfo.intField(spec.field) != sysTestIdle {
+		return nil, errors.New("system test is not available on this device")
+	}
+
+	// the status would not change, so the test could not pass
+	if spec.hasStatus(deviceInfo.EquipmentStatus) {
+		return nil, errors.New("equipment is already running")
+	}
+
+	data := map[string]interface{}{
+		spec.key: sysTestRunning,
+	}
+	if opts.Temperature != nil || opts.Humidity != nil {
+		data["tempHumTestMode"] = true
+	}
+	if opts.Temperature != nil {
+		data["testModeTemperature"] = opts.Temperature.Celsius()
+	}
+	if opts.Humidity != nil {
+		data["testModeHumidity"] = *opts.Humidity
+	}
+
+	start, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.New("json marshal failed")
+	}
+
+	stop, err := json.Marshal(map[string]interface{}{
+		spec.key:          sysTestIdle,
+		"tempHumTestMode": false,
+	})
+	if err != nil {
+		return nil, errors.New("json marshal failed")
+	}
+
+	report = &SystemTestReport{Test: test, Start: time.Now()}
+
+	// the stop is sent even when the start failed, since a write that timed
+	// out may still have reached the device
+	defer func() {
+		stopCtx, cancel := context.WithTimeout(context.Background(), systemTestStopTimeout)
+		defer cancel()
+
+		report.End = time.Now()
+		if stopErr := d.updateDeviceContext(stopCtx, deviceId, stop); stopErr != nil {
+			report.Passed = false
+			report.Failures = append(report.Failures, "ending the test failed")
+			if err == nil {
+				err = errors.New("ending system test failed")
+			}
+			return
+		}
+		report.Ended = true
+	}()
+
+	if err := d.updateDeviceContext(ctx, deviceId, start); err != nil {
+		return report, err
+	}
+
+	if err := d.watchSystemTest(ctx, deviceId, spec, opts, deviceInfo, report); err != nil {
+		report.Failures = append(report.Failures, "test was cancelled")
+		return report, err
+	}
+
+	if report.Samples == 0 {
+		report.Failures = append(report.Failures, "no device data received")
+	}
+	if spec.demand != "" && !report.DemandSeen {
+		report.Failures = append(report.Failures, "no demand reported")
+	}
+	if spec.stage != "" && !report.StageSeen {
+		report.Failures = append(report.Failures, "equipment reported no demand")
+	}
+	if len(spec.statuses) > 0 && !report.StatusSeen {
+		report.Failures = append(report.Failures, "equipment status did not change")
+	}
+	report.Passed = len(report.Failures) == 0
+
+	return report, nil
+}
+
+// watchSystemTest samples the device every Interval until Duration has
+// passed and compares it to baseline. It only returns an error when ctx is
+// done.
+func (d *Daikin) watchSystemTest(ctx context.Context, deviceId string, spec systemTestSpec, opts SystemTestOptions, baseline *DeviceInfo, report *SystemTestReport) error {
+	baseDemand := baseline.demand(spec.demand)
+	baseStage := baseline.demand(spec.stage)
+
+	deadline := time.NewTimer(opts.Duration)
+	defer deadline.Stop()
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline.C:
+			return nil
+		case <-ticker.C:
+		}
+
+		deviceInfo, err := d.getDeviceInfo(ctx, deviceId)
+		if err != nil {
+			continue
+		}
+		report.Samples++
+
+		demand := deviceInfo.demand(spec.demand)
+		if demand > baseDemand {
+			report.DemandSeen = true
+		}
+		if demand > report.MaxDemand {
+			report.MaxDemand = demand
+		}
+
+		if deviceInfo.demand(spec.stage) > baseStage {
+			report.StageSeen = true
+		}
+
+		if spec.hasStatus(deviceInfo.EquipmentStatus) {
+			report.StatusSeen = true
+		}
+	}
+}
+
+// demand returns the percentage in field, or 0 when there is no field or
+// the device reports the demand as not available.
+func (di *DeviceInfo) demand(field string) int {
+	if field == "" {
+		return 0
+	}
+
+	v := di.intField(field)
+	if v < 0 || v > ctDemandMax {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,382 @@
+package daikin_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/nbio/st"
+	"github.com/redgoose/daikin-skyport"
+)
+
+func TestRunSystemTest(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		JSON(map[string]interface{}{"ctSysTestCool": 1, "ctControlAlgorithmCoolDemand": 0, "equipmentStatus": daikin.EquipmentStatusIdle})
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{"ctSysTestCool": 2}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Persist().
+		Reply(200).
+		JSON(map[string]interface{}{"ctSysTestCool": 2, "ctControlAlgorithmCoolDemand": 80, "equipmentStatus": daikin.EquipmentStatusCool})
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{"ctSysTestCool": 1, "tempHumTestMode": false}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	d := daikin.New(email, password)
+	d.InstallerMode = true
+
+	opts := daikin.SystemTestOptions{Duration: 50 * time.Millisecond, Interval: 10 * time.Millisecond}
+	report, err := d.RunSystemTest(context.Background(), deviceId, daikin.SystemTestCool, opts)
+
+	st.Expect(t, err, nil)
+	st.Expect(t, report.Test, daikin.SystemTestCool)
+	st.Expect(t, report.Passed, true)
+	st.Expect(t, report.Ended, true)
+	st.Expect(t, report.DemandSeen, true)
+	st.Expect(t, report.MaxDemand, 80)
+	st.Expect(t, report.StatusSeen, true)
+	st.Expect(t, report.Samples > 0, true)
+	st.Expect(t, len(report.Failures), 0)
+}
+
+func TestRunSystemTestFailed(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Persist().
+		Reply(200).
+		JSON(map[string]interface{}{"ctSysTestHeatPumpHeat": 2, "ctControlAlgorithmHeatDemand": 0, "equipmentStatus": daikin.EquipmentStatusIdle})
+
+	d := daikin.New(email, password)
+	d.InstallerMode = true
+	d.DryRun = true
+
+	temperature := daikin.Fahrenheit(50)
+	humidity := 40
+	opts := daikin.SystemTestOptions{
+		Duration:    50 * time.Millisecond,
+		Interval:    10 * time.Millisecond,
+		Temperature: &temperature,
+		Humidity:    &humidity,
+	}
+	report, err := d.RunSystemTest(context.Background(), deviceId, daikin.SystemTestHeatPumpHeat, opts)
+
+	st.Expect(t, err, nil)
+	st.Expect(t, report.Passed, false)
+	st.Expect(t, report.Ended, true)
+	st.Expect(t, report.Failures, []string{"no demand reported", "equipment reported no demand", "equipment status did not change"})
+
+	writes := d.RecordedWrites()
+	st.Expect(t, len(writes), 2)
+
+	var start, stop map[string]interface{}
+	st.Expect(t, json.Unmarshal(writes[0].Body, &start), nil)
+	st.Expect(t, json.Unmarshal(writes[1].Body, &stop), nil)
+
+	st.Expect(t, start, map[string]interface{}{
+		"ctSysTestHeatPumpHeat": 2.0,
+		"tempHumTestMode":       true,
+		"testModeTemperature":   10.0,
+		"testModeHumidity":      40.0,
+	})
+	st.Expect(t, stop, map[string]interface{}{"ctSysTestHeatPumpHeat": 1.0, "tempHumTestMode": false})
+}
+
+// the fixture is already cooling, so a cool test is refused before it starts
+func TestRunSystemTestAlreadyRunning(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	d := daikin.New(email, password)
+	d.InstallerMode = true
+	d.DryRun = true
+
+	report, err := d.RunSystemTest(context.Background(), deviceId, daikin.SystemTestCool, daikin.SystemTestOptions{})
+
+	st.Expect(t, err, errors.New("equipment is already running"))
+	st.Expect(t, report == nil, true)
+	st.Expect(t, len(d.RecordedWrites()), 0)
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestRunSystemTestDemandNotAvailable(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		JSON(map[string]interface{}{"ctOutdoorUnitType": 6, "ctSystemCapDehumidification": true, "ctSysTestDehumidifier": 1, "ctControlAlgorithmDehumDemand": 200})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Persist().
+		Reply(200).
+		JSON(map[string]interface{}{"ctOutdoorUnitType": 6, "ctSysTestDehumidifier": 2, "ctControlAlgorithmDehumDemand": 200})
+
+	d := daikin.New(email, password)
+	d.InstallerMode = true
+	d.DryRun = true
+
+	opts := daikin.SystemTestOptions{Duration: 50 * time.Millisecond, Interval: 10 * time.Millisecond}
+	report, err := d.RunSystemTest(context.Background(), deviceId, daikin.SystemTestDehumidifier, opts)
+
+	st.Expect(t, err, nil)
+	st.Expect(t, report.Passed, false)
+	st.Expect(t, report.MaxDemand, 0)
+	st.Expect(t, report.Failures, []string{"no demand reported"})
+}
+
+// heat demand and status alone do not show the furnace ran
+func TestRunSystemTestHeatStage(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		JSON(map[string]interface{}{"ctIFCUnitType": 3, "ctOutdoorUnitType": 6, "ctSystemCapGasHeat": true, "ctSysTestGasHeat": 1, "equipmentStatus": daikin.EquipmentStatusIdle})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Persist().
+		Reply(200).
+		JSON(map[string]interface{}{"ctIFCUnitType": 3, "ctOutdoorUnitType": 6, "ctSysTestGasHeat": 2, "ctControlAlgorithmHeatDemand": 70, "ctOutdoorHeatRequestedDemand": 70, "ctIFCHeatRequestedDemandPercent": 0, "equipmentStatus": daikin.EquipmentStatusHeat})
+
+	d := daikin.New(email, password)
+	d.InstallerMode = true
+	d.DryRun = true
+
+	opts := daikin.SystemTestOptions{Duration: 50 * time.Millisecond, Interval: 10 * time.Millisecond}
+	report, err := d.RunSystemTest(context.Background(), deviceId, daikin.SystemTestGasHeat, opts)
+
+	st.Expect(t, err, nil)
+	st.Expect(t, report.Passed, false)
+	st.Expect(t, report.DemandSeen, true)
+	st.Expect(t, report.StatusSeen, true)
+	st.Expect(t, report.StageSeen, false)
+	st.Expect(t, report.Failures, []string{"equipment reported no demand"})
+}
+
+func TestRunSystemTestHeatPumpHeat(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		JSON(map[string]interface{}{"ctIFCUnitType": 3, "ctOutdoorUnitType": 6, "ctSystemCapCompressorHeat": true, "ctSysTestHeatPumpHeat": 1, "equipmentStatus": daikin.EquipmentStatusIdle})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Persist().
+		Reply(200).
+		JSON(map[string]interface{}{"ctIFCUnitType": 3, "ctOutdoorUnitType": 6, "ctSysTestHeatPumpHeat": 2, "ctControlAlgorithmHeatDemand": 70, "ctOutdoorHeatRequestedDemand": 70, "equipmentStatus": daikin.EquipmentStatusHeat})
+
+	d := daikin.New(email, password)
+	d.InstallerMode = true
+	d.DryRun = true
+
+	opts := daikin.SystemTestOptions{Duration: 50 * time.Millisecond, Interval: 10 * time.Millisecond}
+	report, err := d.RunSystemTest(context.Background(), deviceId, daikin.SystemTestHeatPumpHeat, opts)
+
+	st.Expect(t, err, nil)
+	st.Expect(t, report.Passed, true)
+	st.Expect(t, report.StageSeen, true)
+	st.Expect(t, report.MaxDemand, 70)
+}
+
+func TestRunSystemTestCancelled(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Persist().
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	d := daikin.New(email, password)
+	d.InstallerMode = true
+	d.DryRun = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	opts := daikin.SystemTestOptions{Duration: time.Minute, Interval: 10 * time.Millisecond}
+	report, err := d.RunSystemTest(ctx, deviceId, daikin.SystemTestFan, opts)
+
+	st.Expect(t, err, context.DeadlineExceeded)
+	st.Expect(t, report.Passed, false)
+	st.Expect(t, report.Ended, true)
+	st.Expect(t, report.Failures, []string{"test was cancelled"})
+
+	// the test is ended even though ctx is done
+	writes := d.RecordedWrites()
+	st.Expect(t, len(writes), 2)
+	st.Expect(t, string(writes[1].Body), `{"ctSysTestFan":1,"tempHumTestMode":false}`)
+}
+
+func TestRunSystemTestGuards(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		JSON(map[string]interface{}{"ctSysTestCool": 1, "ctSysTestFan": 2})
+
+	d := daikin.New(email, password)
+	ctx := context.Background()
+
+	_, err := d.RunSystemTest(ctx, deviceId, daikin.SystemTestCool, daikin.SystemTestOptions{})
+	st.Expect(t, err, errors.New("installer mode is required to run system tests"))
+
+	d.InstallerMode = true
+
+	_, err = d.RunSystemTest(ctx, deviceId, daikin.SystemTestCool, daikin.SystemTestOptions{Duration: time.Hour})
+	st.Expect(t, err, errors.New("system test duration outside of allowable range"))
+
+	humidity := 120
+	_, err = d.RunSystemTest(ctx, deviceId, daikin.SystemTestCool, daikin.SystemTestOptions{Humidity: &humidity})
+	st.Expect(t, err, errors.New("test mode humidity outside of allowable range"))
+
+	_, err = d.RunSystemTest(ctx, deviceId, daikin.SystemTestVentilator, daikin.SystemTestOptions{})
+	st.Expect(t, err, errors.New("system test is not available on this device"))
+
+	_, err = d.RunSystemTest(ctx, deviceId, daikin.SystemTestCool, daikin.SystemTestOptions{})
+	st.Expect(t, err, errors.New("a system test is already running"))
+
+	st.Expect(t, gock.IsDone(), true)
+}
@@ -0,0 +1,86 @@
+package daikin
+
+import (
+	"fmt"
+	"math"
+)
+
+type TemperatureUnit uint8
+
+const (
+	TemperatureUnitFahrenheit TemperatureUnit = iota
+	TemperatureUnitCelsius
+)
+
+func (u TemperatureUnit) String() string {
+	if u == TemperatureUnitFahrenheit {
+		return "°F"
+	}
+	return "°C"
+}
+
+// Temperature is stored in celsius, which is what the device uses internally.
+type Temperature float32
+
+// setpoints on the device have a 0.5°C resolution
+const setpointStep = 0.5
+
+func Celsius(value float32) Temperature {
+	return Temperature(value)
+}
+
+func Fahrenheit(value float32) Temperature {
+	return Temperature((float64(value) - 32) * 5 / 9)
+}
+
+func NewTemperature(value float32, units TemperatureUnit) Temperature {
+	if units == TemperatureUnitFahrenheit {
+		return Fahrenheit(value)
+	}
+	return Celsius(value)
+}
+
+func (t Temperature) Celsius() float32 {
+	return float32(t)
+}
+
+// setpoint rounds to the device's setpoint resolution, so the same value
+// always maps to the same setpoint whichever unit it was given in.
+func (t Temperature) setpoint() float32 {
+	return float32(math.Round(float64(t)/setpointStep) * setpointStep)
+}
+
+func (t Temperature) Fahrenheit() float32 {
+	f := float64(t)*9/5 + 32
+	return float32(math.Round(f*10) / 10)
+}
+
+func (t Temperature) In(units TemperatureUnit) float32 {
+	if units == TemperatureUnitFahrenheit {
+		return t.Fahrenheit()
+	}
+	return t.Celsius()
+}
+
+func (t Temperature) Format(units TemperatureUnit) string {
+	if units == TemperatureUnitFahrenheit {
+		return fmt.Sprintf("%.0f%s", t.Fahrenheit(), units)
+	}
+	return fmt.Sprintf("%.1f%s", t.Celsius(), units)
+}
+
+func (t Temperature) String() string {
+	return t.Format(TemperatureUnitCelsius)
+}
+
+// TemperatureDelta is a temperature difference in celsius, such as superheat
+// or a supply/return split.
+type TemperatureDelta float32
+
+func (t TemperatureDelta) Celsius() float32 {
+	return float32(t)
+}
+
+func (t TemperatureDelta) Fahrenheit() float32 {
+	return float32(math.Round(float64(t)*9/5*10) / 10)
+}
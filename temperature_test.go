@@ -0,0 +1,36 @@
+package daikin_test
+
+import (
+	"testing"
+
+	"github.com/nbio/st"
+	"github.com/redgoose/daikin-skyport"
+)
+
+func TestFahrenheit(t *testing.T) {
+	st.Expect(t, daikin.Fahrenheit(32), daikin.Celsius(0))
+	st.Expect(t, daikin.Fahrenheit(68), daikin.Celsius(20))
+	st.Expect(t, round(daikin.Fahrenheit(72).Celsius()), 22.2)
+	st.Expect(t, round(daikin.Fahrenheit(20).Celsius()), -6.7)
+
+	// readings are not rounded to the setpoint resolution
+	st.Expect(t, daikin.Fahrenheit(72).Fahrenheit(), float32(72))
+	st.Expect(t, daikin.Celsius(22.3).Celsius(), float32(22.3))
+}
+
+func TestTemperatureConversion(t *testing.T) {
+	temp := daikin.Celsius(22.5)
+
+	st.Expect(t, temp.Celsius(), float32(22.5))
+	st.Expect(t, temp.Fahrenheit(), float32(72.5))
+	st.Expect(t, temp.In(daikin.TemperatureUnitFahrenheit), float32(72.5))
+	st.Expect(t, daikin.NewTemperature(temp.Fahrenheit(), daikin.TemperatureUnitFahrenheit), temp)
+}
+
+func TestTemperatureFormat(t *testing.T) {
+	temp := daikin.Celsius(22)
+
+	st.Expect(t, temp.Format(daikin.TemperatureUnitCelsius), "22.0°C")
+	st.Expect(t, temp.Format(daikin.TemperatureUnitFahrenheit), "72°F")
+	st.Expect(t, temp.String(), "22.0°C")
+}
@@ -1,5 +1,10 @@
 package daikin
 
+import (
+	"strings"
+	"time"
+)
+
 type Token struct {
 	AccessToken          string `json:"accessToken"`
 	AccessTokenExpiresIn int    `json:"accessTokenExpiresIn"`
@@ -40,6 +45,29 @@ const (
 	ModeEmHeat
 )
 
+var modes = map[Mode]string{
+	ModeOff:    "Off",
+	ModeHeat:   "Heat",
+	ModeCool:   "Cool",
+	ModeAuto:   "Auto",
+	ModeEmHeat: "Emergency Heat",
+}
+
+func (m Mode) String() string {
+	return modes[m]
+}
+
+// ModeLimit restricts the modes offered on the thermostat. Devices that do
+// not report a limit use ModeLimitNone.
+type ModeLimit uint8
+
+const (
+	ModeLimitNone ModeLimit = iota
+	ModeLimitAll
+	ModeLimitHeatOnly
+	ModeLimitCoolOnly
+)
+
 type FanCirculateSpeed uint8
 
 const (
@@ -56,6 +84,268 @@ const (
 	FanCirculateSched
 )
 
+type FanCirculateDuration uint8
+
+const (
+	FanCirculateDurationFull FanCirculateDuration = iota
+	FanCirculateDuration5Min
+	FanCirculateDuration15Min
+	FanCirculateDuration30Min
+	FanCirculateDuration45Min
+)
+
+type FanSettings struct {
+	Mode          FanCirculate
+	Speed         FanCirculateSpeed
+	Active        bool
+	ScheduleStart time.Duration
+	ScheduleStop  time.Duration
+	DutyCycle     FanCirculateDuration
+	ExtendCool    time.Duration
+	ExtendHeat    time.Duration
+}
+
+type DeviceNamePreset uint8
+
+const (
+	DeviceNameCustom DeviceNamePreset = iota
+	DeviceNameMainRoom
+	DeviceNameUpstairs
+	DeviceNameDownstairs
+	DeviceNameHallway
+	DeviceNameBedroom
+	DeviceNameMasterBedroom
+	DeviceNameLivingRoom
+	DeviceNameFamilyRoom
+	DeviceNameKitchen
+	DeviceNameDiningRoom
+	DeviceNameBasement
+	DeviceNameOffice
+)
+
+var deviceNamePresets = map[DeviceNamePreset]string{
+	DeviceNameMainRoom:      "Main Room",
+	DeviceNameUpstairs:      "Upstairs",
+	DeviceNameDownstairs:    "Downstairs",
+	DeviceNameHallway:       "Hallway",
+	DeviceNameBedroom:       "Bedroom",
+	DeviceNameMasterBedroom: "Master Bedroom",
+	DeviceNameLivingRoom:    "Living Room",
+	DeviceNameFamilyRoom:    "Family Room",
+	DeviceNameKitchen:       "Kitchen",
+	DeviceNameDiningRoom:    "Dining Room",
+	DeviceNameBasement:      "Basement",
+	DeviceNameOffice:        "Office",
+}
+
+func (p DeviceNamePreset) String() string {
+	return deviceNamePresets[p]
+}
+
+// AirQualityLevel follows the EPA air quality index categories.
+type AirQualityLevel uint8
+
+const (
+	AirQualityGood AirQualityLevel = iota
+	AirQualityModerate
+	AirQualityUnhealthySensitive
+	AirQualityUnhealthy
+	AirQualityVeryUnhealthy
+	AirQualityHazardous
+)
+
+var airQualityLevels = map[AirQualityLevel]string{
+	AirQualityGood:               "Good",
+	AirQualityModerate:           "Moderate",
+	AirQualityUnhealthySensitive: "Unhealthy for Sensitive Groups",
+	AirQualityUnhealthy:          "Unhealthy",
+	AirQualityVeryUnhealthy:      "Very Unhealthy",
+	AirQualityHazardous:          "Hazardous",
+}
+
+func (l AirQualityLevel) String() string {
+	return airQualityLevels[l]
+}
+
+type WeatherCondition uint8
+
+const (
+	WeatherUnknown WeatherCondition = iota
+	WeatherClear
+	WeatherSunny
+	WeatherMostlySunny
+	WeatherPartlySunny
+	WeatherPartlyCloudy
+	WeatherMostlyCloudy
+	WeatherCloudy
+	WeatherHazy
+	WeatherFog
+	WeatherChanceRain
+	WeatherRain
+	WeatherChanceThunderstorms
+	WeatherThunderstorms
+	WeatherChanceSleet
+	WeatherSleet
+	WeatherChanceFlurries
+	WeatherFlurries
+	WeatherChanceSnow
+	WeatherSnow
+)
+
+// weather icons as sent to the thermostat
+var weatherIcons = map[string]WeatherCondition{
+	"clear":          WeatherClear,
+	"sunny":          WeatherSunny,
+	"mostlysunny":    WeatherMostlySunny,
+	"partlysunny":    WeatherPartlySunny,
+	"partlycloudy":   WeatherPartlyCloudy,
+	"mostlycloudy":   WeatherMostlyCloudy,
+	"cloudy":         WeatherCloudy,
+	"hazy":           WeatherHazy,
+	"fog":            WeatherFog,
+	"chancerain":     WeatherChanceRain,
+	"rain":           WeatherRain,
+	"chancetstorms":  WeatherChanceThunderstorms,
+	"tstorms":        WeatherThunderstorms,
+	"chancesleet":    WeatherChanceSleet,
+	"sleet":          WeatherSleet,
+	"chanceflurries": WeatherChanceFlurries,
+	"flurries":       WeatherFlurries,
+	"chancesnow":     WeatherChanceSnow,
+	"snow":           WeatherSnow,
+}
+
+func ParseWeatherCondition(icon string) WeatherCondition {
+	return weatherIcons[strings.ToLower(strings.TrimSpace(icon))]
+}
+
+func (c WeatherCondition) String() string {
+	for icon, condition := range weatherIcons {
+		if condition == c {
+			return icon
+		}
+	}
+	return "unknown"
+}
+
+type DemandResponseState uint8
+
+const (
+	DemandResponseIdle DemandResponseState = iota
+	DemandResponsePending
+	DemandResponseActive
+)
+
+type DemandResponseStatus uint8
+
+const (
+	DemandResponseStatusNone DemandResponseStatus = iota
+	DemandResponseOptedIn
+	DemandResponseOptedOut
+)
+
+// DemandResponseAction is how setpoints are adjusted during an event.
+type DemandResponseAction uint8
+
+const (
+	DemandResponseOffset DemandResponseAction = iota
+	DemandResponseStatic
+)
+
+type DealerAccessStatus uint8
+
+const (
+	DealerAccessNoResponse DealerAccessStatus = iota
+	DealerAccessGranted
+	DealerAccessDenied
+	DealerAccessRevoked
+)
+
+type SystemTest uint8
+
+const (
+	SystemTestCool SystemTest = iota
+	SystemTestFan
+	SystemTestGasHeat
+	SystemTestElectricHeat
+	SystemTestHeatPumpHeat
+	SystemTestAuxHeater
+	SystemTestHumidifier
+	SystemTestDehumidifier
+	SystemTestVentilator
+)
+
+var systemTestNames = map[SystemTest]string{
+	SystemTestCool:         "Cool",
+	SystemTestFan:          "Fan",
+	SystemTestGasHeat:      "Gas Heat",
+	SystemTestElectricHeat: "Electric Heat",
+	SystemTestHeatPumpHeat: "Heat Pump Heat",
+	SystemTestAuxHeater:    "Aux Heater",
+	SystemTestHumidifier:   "Humidifier",
+	SystemTestDehumidifier: "Dehumidifier",
+	SystemTestVentilator:   "Ventilator",
+}
+
+func (s SystemTest) String() string {
+	return systemTestNames[s]
+}
+
+// OneCleanAction is what the thermostat does when a OneClean trigger is
+// reached.
+type OneCleanAction uint8
+
+const (
+	OneCleanActionRunFan OneCleanAction = iota
+	OneCleanActionNotify
+)
+
+type OneCleanConfig struct {
+	Duration        time.Duration
+	Speed           FanCirculateSpeed
+	Action          OneCleanAction
+	AQITrigger      int
+	ParticleTrigger int
+	VOCTrigger      int
+}
+
+type Calibration struct {
+	TempOffset        float32
+	HumOffset         int
+	RawTemperature    float32
+	RawHumidity       int
+	DynamicTempOffset float32
+	DynamicHumOffset  int
+}
+
+// LockoutSettings are the heat lockout settings of a device. Units is the
+// unit configured on the device, for displaying the temperatures.
+type LockoutSettings struct {
+	Units                           TemperatureUnit
+	AuxHeatLockoutEnabled           bool
+	AuxHeatLockoutTemp              Temperature
+	HeatPumpLockoutEnabled          bool
+	HeatPumpLockoutTemp             Temperature
+	AuxHeaterHeatPumpLockoutEnabled bool
+	AuxHeaterHeatPumpLockoutTemp    Temperature
+	AuxHeaterHeatPumpLockoutActive  bool
+	DualFuelFurnaceLockoutEnabled   bool
+	EmHeatAvailable                 bool
+}
+
+// LockoutSettingsUpdate lists the lockout settings to change. Fields left nil
+// keep their current value on the device.
+type LockoutSettingsUpdate struct {
+	AuxHeatLockoutEnabled           *bool
+	AuxHeatLockoutTemp              *Temperature
+	HeatPumpLockoutEnabled          *bool
+	HeatPumpLockoutTemp             *Temperature
+	AuxHeaterHeatPumpLockoutEnabled *bool
+	AuxHeaterHeatPumpLockoutTemp    *Temperature
+	DualFuelFurnaceLockoutEnabled   *bool
+	EmHeatAvailable                 *bool
+}
+
 type DeviceInfo struct {
 	AdrAction                                               int               `json:"adrAction"`
 	AdrActualStart                                          int               `json:"adrActualStart"`
@@ -0,0 +1,146 @@
+package daikin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// DeviceUpdate collects several changes and sends them to the device in a
+// single request. Create one with Daikin.Update.
+type DeviceUpdate struct {
+	d        *Daikin
+	deviceId string
+	data     map[string]interface{}
+	cool     *float32
+	heat     *float32
+}
+
+func (d *Daikin) Update(deviceId string) *DeviceUpdate {
+	return &DeviceUpdate{
+		d:        d,
+		deviceId: deviceId,
+		data:     map[string]interface{}{},
+	}
+}
+
+func (u *DeviceUpdate) Mode(mode Mode) *DeviceUpdate {
+	u.data["mode"] = mode
+	return u
+}
+
+func (u *DeviceUpdate) CoolSetpoint(temp Temperature) *DeviceUpdate {
+	cool := temp.setpoint()
+	u.cool = &cool
+	return u
+}
+
+func (u *DeviceUpdate) HeatSetpoint(temp Temperature) *DeviceUpdate {
+	heat := temp.setpoint()
+	u.heat = &heat
+	return u
+}
+
+func (u *DeviceUpdate) FanMode(fan_mode FanCirculate) *DeviceUpdate {
+	u.data["fanCirculate"] = fan_mode
+	return u
+}
+
+func (u *DeviceUpdate) FanSpeed(fan_speed FanCirculateSpeed) *DeviceUpdate {
+	u.data["fanCirculateSpeed"] = fan_speed
+	return u
+}
+
+func (u *DeviceUpdate) FanClean(fan_clean_active bool) *DeviceUpdate {
+	u.data["oneCleanFanActive"] = fan_clean_active
+	return u
+}
+
+func (u *DeviceUpdate) Apply(ctx context.Context) error {
+	body, err := u.build(ctx)
+	if err != nil {
+		return err
+	}
+
+	return u.d.updateDeviceContext(ctx, u.deviceId, body)
+}
+
+func (u *DeviceUpdate) hasSetpoints() bool {
+	return u.cool != nil || u.heat != nil
+}
+
+// usesFan reports whether the update runs or configures the indoor fan.
+func (u *DeviceUpdate) usesFan() bool {
+	fan_mode, hasFanMode := u.data["fanCirculate"].(FanCirculate)
+	_, hasFanSpeed := u.data["fanCirculateSpeed"]
+	fan_clean_active, _ := u.data["oneCleanFanActive"].(bool)
+
+	return hasFanMode && fan_mode != FanCirculateOff || hasFanSpeed || fan_clean_active
+}
+
+func (u *DeviceUpdate) build(ctx context.Context) ([]byte, error) {
+	if len(u.data) == 0 && !u.hasSetpoints() {
+		return nil, errors.New("no changes to apply")
+	}
+
+	mode, hasMode := u.data["mode"].(Mode)
+	if hasMode && mode > ModeEmHeat {
+		return nil, errors.New("invalid mode")
+	}
+
+	if fan_mode, ok := u.data["fanCirculate"].(FanCirculate); ok && fan_mode > FanCirculateSched {
+		return nil, errors.New("invalid fan circulate mode")
+	}
+
+	if fan_speed, ok := u.data["fanCirculateSpeed"].(FanCirculateSpeed); ok && fan_speed > FanCirculateSpeedHigh {
+		return nil, errors.New("invalid fan circulate speed")
+	}
+
+	if u.hasSetpoints() {
+		if err := checkSetpoints(u.cool, u.heat); err != nil {
+			return nil, err
+		}
+	}
+
+	data := map[string]interface{}{}
+	for k, v := range u.data {
+		data[k] = v
+	}
+
+	if u.hasSetpoints() || hasMode && mode != ModeOff || u.usesFan() {
+		deviceInfo, err := u.d.getDeviceInfo(ctx, u.deviceId)
+		if err != nil {
+			return nil, errors.New("get device info failed")
+		}
+
+		if u.usesFan() {
+			if err := deviceInfo.Capabilities().checkFan(); err != nil {
+				return nil, err
+			}
+		}
+
+		if hasMode {
+			if err := deviceInfo.Capabilities().checkMode(mode); err != nil {
+				return nil, err
+			}
+		}
+
+		if u.hasSetpoints() {
+			params, err := deviceInfo.resolveSetpoints(u.cool, u.heat)
+			if err != nil {
+				return nil, err
+			}
+
+			data["cspHome"] = params.CoolSetpoint
+			data["hspHome"] = params.HeatSetpoint
+			data["schedOverride"] = 1
+		}
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.New("json marshal failed")
+	}
+
+	return body, nil
+}
@@ -0,0 +1,137 @@
+package daikin_test
+
+import (
+	"context"
+	"errors"
+	"path"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/nbio/st"
+	"github.com/redgoose/daikin-skyport"
+)
+
+func TestUpdateApply(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{
+			"mode":              daikin.ModeCool,
+			"cspHome":           22,
+			"hspHome":           17.5,
+			"schedOverride":     1,
+			"fanCirculateSpeed": daikin.FanCirculateSpeedLow,
+		}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	d := daikin.New(email, password)
+	err := d.Update(deviceId).
+		Mode(daikin.ModeCool).
+		CoolSetpoint(22).
+		FanSpeed(daikin.FanCirculateSpeedLow).
+		Apply(context.Background())
+
+	st.Expect(t, err, nil)
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestUpdateApplyWithoutDeviceInfo(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{"fanCirculate": daikin.FanCirculateOff, "oneCleanFanActive": false}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	d := daikin.New(email, password)
+	err := d.Update(deviceId).
+		FanMode(daikin.FanCirculateOff).
+		FanClean(false).
+		Apply(context.Background())
+
+	st.Expect(t, err, nil)
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestUpdateApplyInvalid(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	d := daikin.New(email, password)
+
+	err := d.Update(deviceId).Apply(context.Background())
+	st.Expect(t, err, errors.New("no changes to apply"))
+
+	err = d.Update(deviceId).Mode(daikin.Mode(9)).Apply(context.Background())
+	st.Expect(t, err, errors.New("invalid mode"))
+
+	err = d.Update(deviceId).CoolSetpoint(20).HeatSetpoint(22).Apply(context.Background())
+	st.Expect(t, err, errors.New("cool setpoint can not be lower than heat setpoint"))
+
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestUpdateApplyFreezingSetpoint(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	d := daikin.New(email, password)
+
+	// 0°C is a setpoint, not an unset value
+	err := d.Update(deviceId).HeatSetpoint(daikin.Fahrenheit(32)).Apply(context.Background())
+	st.Expect(t, err, errors.New("setpoint(s) outside of allowable range"))
+
+	st.Expect(t, gock.IsDone(), true)
+}
@@ -0,0 +1,201 @@
+package daikin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+const (
+	defaultVerifyTimeout  = 30 * time.Second
+	defaultVerifyInterval = 2 * time.Second
+)
+
+// VerifyOptions controls how long to wait for the device to report written
+// values back. Zero values fall back to the defaults.
+type VerifyOptions struct {
+	Timeout  time.Duration
+	Interval time.Duration
+}
+
+// VerifyResult lists written fields by their JSON name. A field is reverted
+// when the device reported the requested value at some point and then
+// changed it again.
+type VerifyResult struct {
+	Confirmed []string
+	Pending   []string
+	Reverted  []string
+}
+
+func (r *VerifyResult) Ok() bool {
+	return len(r.Pending) == 0 && len(r.Reverted) == 0
+}
+
+type VerifyError struct {
+	Result *VerifyResult
+}
+
+func (e *VerifyError) Error() string {
+	return fmt.Sprintf("update not confirmed by device (pending: %v, reverted: %v)", e.Result.Pending, e.Result.Reverted)
+}
+
+func (d *Daikin) UpdateDeviceRawVerified(ctx context.Context, deviceId string, json string, opts VerifyOptions) (*VerifyResult, error) {
+	if err := d.putDeviceData(ctx, deviceId, []byte(json)); err != nil {
+		return nil, err
+	}
+
+	if d.DryRun {
+		return unverifiedResult([]byte(json))
+	}
+
+	return d.verifyUpdate(ctx, deviceId, []byte(json), opts)
+}
+
+func (u *DeviceUpdate) ApplyVerified(ctx context.Context, opts VerifyOptions) (*VerifyResult, error) {
+	body, err := u.build(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.d.putDeviceData(ctx, u.deviceId, body); err != nil {
+		return nil, err
+	}
+
+	if u.d.DryRun {
+		return unverifiedResult(body)
+	}
+
+	return u.d.verifyUpdate(ctx, u.deviceId, body, opts)
+}
+
+func (d *Daikin) verifyUpdate(ctx context.Context, deviceId string, body []byte, opts VerifyOptions) (*VerifyResult, error) {
+	var requested map[string]interface{}
+	if err := json.Unmarshal(body, &requested); err != nil {
+		return nil, errors.New("json decode failed")
+	}
+
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultVerifyTimeout
+	}
+
+	if opts.Interval <= 0 {
+		opts.Interval = defaultVerifyInterval
+	}
+
+	pollCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	seen := map[string]bool{}
+	current := map[string]interface{}{}
+
+	// without a single successful read nothing is known about the update, so
+	// the read error is returned instead of reporting every field as pending
+	read := false
+	var readErr error
+
+	for {
+		deviceInfo, err := d.getDeviceInfo(pollCtx, deviceId)
+		if err != nil && pollCtx.Err() == nil {
+			readErr = err
+		}
+		if err == nil {
+			read = true
+			current, err = deviceInfoFields(deviceInfo)
+			if err != nil {
+				return nil, err
+			}
+
+			confirmed := 0
+			for key, value := range requested {
+				if reflect.DeepEqual(current[key], value) {
+					seen[key] = true
+					confirmed++
+				}
+			}
+
+			if confirmed == len(requested) {
+				break
+			}
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		timer := time.NewTimer(opts.Interval)
+		select {
+		case <-pollCtx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+
+		if pollCtx.Err() != nil {
+			break
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if !read {
+		if readErr == nil {
+			readErr = errors.New("get device info failed")
+		}
+		return nil, readErr
+	}
+
+	result := &VerifyResult{}
+	for key, value := range requested {
+		switch {
+		case reflect.DeepEqual(current[key], value):
+			result.Confirmed = append(result.Confirmed, key)
+		case seen[key]:
+			result.Reverted = append(result.Reverted, key)
+		default:
+			result.Pending = append(result.Pending, key)
+		}
+	}
+
+	sort.Strings(result.Confirmed)
+	sort.Strings(result.Pending)
+	sort.Strings(result.Reverted)
+
+	return result, nil
+}
+
+// unverifiedResult reports every field as pending, used when nothing was sent.
+func unverifiedResult(body []byte) (*VerifyResult, error) {
+	var requested map[string]interface{}
+	if err := json.Unmarshal(body, &requested); err != nil {
+		return nil, errors.New("json decode failed")
+	}
+
+	result := &VerifyResult{}
+	for key := range requested {
+		result.Pending = append(result.Pending, key)
+	}
+	sort.Strings(result.Pending)
+
+	return result, nil
+}
+
+// deviceInfoFields returns the device info keyed by JSON name so it can be
+// compared against a raw update body.
+func deviceInfoFields(deviceInfo *DeviceInfo) (map[string]interface{}, error) {
+	body, err := json.Marshal(deviceInfo)
+	if err != nil {
+		return nil, errors.New("json marshal failed")
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, errors.New("json decode failed")
+	}
+
+	return fields, nil
+}
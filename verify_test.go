@@ -0,0 +1,129 @@
+package daikin_test
+
+import (
+	"context"
+	"errors"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/nbio/st"
+	"github.com/redgoose/daikin-skyport"
+)
+
+func TestUpdateDeviceRawVerifiedConfirmed(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		JSON(map[string]interface{}{"mode": daikin.ModeCool, "cspHome": 22}).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		File(path.Join("fixtures", "device_info.json"))
+
+	d := daikin.New(email, password)
+	result, err := d.UpdateDeviceRawVerified(context.Background(), deviceId, `{"mode": 2, "cspHome": 22}`, daikin.VerifyOptions{})
+
+	st.Expect(t, err, nil)
+	st.Expect(t, result.Ok(), true)
+	st.Expect(t, result.Confirmed, []string{"cspHome", "mode"})
+	st.Expect(t, gock.IsDone(), true)
+}
+
+func TestUpdateDeviceRawVerifiedReverted(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		JSON(map[string]interface{}{"mode": daikin.ModeOff, "fanCirculate": daikin.FanCirculateOff, "cspHome": 22})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Persist().
+		Reply(200).
+		JSON(map[string]interface{}{"mode": daikin.ModeCool, "fanCirculate": daikin.FanCirculateOff, "cspHome": 22})
+
+	d := daikin.New(email, password)
+	d.Verify = &daikin.VerifyOptions{Timeout: 50 * time.Millisecond, Interval: 10 * time.Millisecond}
+
+	err := d.UpdateDeviceRaw(deviceId, `{"mode": 0, "fanCirculate": 1, "cspHome": 22}`)
+
+	verifyErr, ok := err.(*daikin.VerifyError)
+	st.Expect(t, ok, true)
+	st.Expect(t, verifyErr.Result.Confirmed, []string{"cspHome"})
+	st.Expect(t, verifyErr.Result.Pending, []string{"fanCirculate"})
+	st.Expect(t, verifyErr.Result.Reverted, []string{"mode"})
+}
+
+func TestUpdateDeviceRawVerifiedReadFailed(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Put("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Reply(200).
+		JSON(map[string]string{"message": "Write sent"})
+
+	gock.New(urlBase).
+		Get("/deviceData/"+deviceId).
+		MatchHeader("Authorization", "Bearer "+accessToken).
+		Persist().
+		Reply(401)
+
+	d := daikin.New(email, password)
+	opts := daikin.VerifyOptions{Timeout: 50 * time.Millisecond, Interval: 10 * time.Millisecond}
+	result, err := d.UpdateDeviceRawVerified(context.Background(), deviceId, `{"mode": 2}`, opts)
+
+	// a device that was never read is not reported as pending
+	st.Expect(t, err, errors.New("get device info request returned a non-success response: 401 Unauthorized"))
+	st.Expect(t, result == nil, true)
+}
@@ -0,0 +1,286 @@
+package daikin
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	defaultWatchInterval   = time.Minute
+	defaultWatchMaxBackoff = 10 * time.Minute
+
+	rfSensorCount = 5
+)
+
+type Event interface {
+	Device() string
+}
+
+type ModeChanged struct {
+	DeviceId string
+	Time     time.Time
+	Old      Mode
+	New      Mode
+}
+
+type SetpointChanged struct {
+	DeviceId string
+	Time     time.Time
+	OldCool  Temperature
+	NewCool  Temperature
+	OldHeat  Temperature
+	NewHeat  Temperature
+}
+
+type EquipmentStatusChanged struct {
+	DeviceId string
+	Time     time.Time
+	Old      EquipmentStatus
+	New      EquipmentStatus
+}
+
+type FaultRaised struct {
+	DeviceId string
+	Time     time.Time
+	Board    string
+	Code     int
+	Critical bool
+}
+
+type FaultCleared struct {
+	DeviceId string
+	Time     time.Time
+	Board    string
+	Code     int
+	Critical bool
+}
+
+type SensorOffline struct {
+	DeviceId string
+	Time     time.Time
+	Sensor   int
+	Name     string
+}
+
+type FilterDue struct {
+	DeviceId string
+	Time     time.Time
+	Filter   string
+}
+
+type PollFailed struct {
+	DeviceId string
+	Time     time.Time
+	Err      error
+}
+
+func (e ModeChanged) Device() string            { return e.DeviceId }
+func (e SetpointChanged) Device() string        { return e.DeviceId }
+func (e EquipmentStatusChanged) Device() string { return e.DeviceId }
+func (e FaultRaised) Device() string            { return e.DeviceId }
+func (e FaultCleared) Device() string           { return e.DeviceId }
+func (e SensorOffline) Device() string          { return e.DeviceId }
+func (e FilterDue) Device() string              { return e.DeviceId }
+func (e PollFailed) Device() string             { return e.DeviceId }
+
+// Watcher polls devices and emits an Event for every change it notices
+// between two successive samples. A zero Interval or MaxBackoff falls back
+// to the default.
+type Watcher struct {
+	Interval   time.Duration
+	Jitter     time.Duration
+	MaxBackoff time.Duration
+	d          *Daikin
+	deviceIds  []string
+}
+
+func (d *Daikin) NewWatcher(deviceIds ...string) *Watcher {
+	return &Watcher{
+		Interval:   defaultWatchInterval,
+		MaxBackoff: defaultWatchMaxBackoff,
+		d:          d,
+		deviceIds:  deviceIds,
+	}
+}
+
+// Run starts polling and returns the event channel, which is closed once ctx
+// is cancelled and all pollers have stopped.
+func (w *Watcher) Run(ctx context.Context) <-chan Event {
+	events := make(chan Event, 16)
+
+	var wg sync.WaitGroup
+	for _, deviceId := range w.deviceIds {
+		wg.Add(1)
+		go func(deviceId string) {
+			defer wg.Done()
+			w.poll(ctx, deviceId, events)
+		}(deviceId)
+	}
+
+	go func() {
+		wg.Wait()
+		close(events)
+	}()
+
+	return events
+}
+
+func (w *Watcher) poll(ctx context.Context, deviceId string, events chan<- Event) {
+	var prev *DeviceInfo
+	failures := 0
+
+	for {
+		deviceInfo, err := w.d.getDeviceInfo(ctx, deviceId)
+		if ctx.Err() != nil {
+			return
+		}
+
+		wait := w.interval()
+		if err != nil {
+			failures++
+			wait = w.backoff(failures)
+
+			if !send(ctx, events, PollFailed{DeviceId: deviceId, Time: time.Now(), Err: err}) {
+				return
+			}
+		} else {
+			failures = 0
+
+			if prev != nil {
+				for _, event := range deviceEvents(deviceId, prev, deviceInfo, time.Now()) {
+					if !send(ctx, events, event) {
+						return
+					}
+				}
+			}
+			prev = deviceInfo
+		}
+
+		if w.Jitter > 0 {
+			wait += time.Duration(rand.Int63n(int64(w.Jitter)))
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+func (w *Watcher) backoff(failures int) time.Duration {
+	wait, limit := w.interval(), w.maxBackoff()
+	for i := 1; i < failures && wait < limit; i++ {
+		wait *= 2
+	}
+	if wait > limit {
+		wait = limit
+	}
+	return wait
+}
+
+func (w *Watcher) interval() time.Duration {
+	if w.Interval <= 0 {
+		return defaultWatchInterval
+	}
+	return w.Interval
+}
+
+func (w *Watcher) maxBackoff() time.Duration {
+	if w.MaxBackoff <= 0 {
+		return defaultWatchMaxBackoff
+	}
+	return w.MaxBackoff
+}
+
+func send(ctx context.Context, events chan<- Event, event Event) bool {
+	select {
+	case events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+var faultBoards = []string{"Stat", "IFC", "AH", "EEVCoil", "Outdoor"}
+
+var filterAlerts = []struct {
+	field  string
+	filter string
+}{
+	{"AlertMediaAirFilterActive", "media"},
+	{"AlertElectronicAirFilterActive", "electronic"},
+	{"AlertHEPAAirFilterActive", "hepa"},
+	{"AlertDehumFilterActive", "dehumidifier"},
+}
+
+func deviceEvents(deviceId string, old *DeviceInfo, new *DeviceInfo, now time.Time) []Event {
+	var events []Event
+
+	if old.Mode != new.Mode {
+		events = append(events, ModeChanged{DeviceId: deviceId, Time: now, Old: old.Mode, New: new.Mode})
+	}
+
+	if old.CspHome != new.CspHome || old.HspHome != new.HspHome {
+		events = append(events, SetpointChanged{
+			DeviceId: deviceId,
+			Time:     now,
+			OldCool:  Celsius(old.CspHome),
+			NewCool:  Celsius(new.CspHome),
+			OldHeat:  Celsius(old.HspHome),
+			NewHeat:  Celsius(new.HspHome),
+		})
+	}
+
+	if old.EquipmentStatus != new.EquipmentStatus {
+		events = append(events, EquipmentStatusChanged{DeviceId: deviceId, Time: now, Old: old.EquipmentStatus, New: new.EquipmentStatus})
+	}
+
+	for _, board := range faultBoards {
+		for _, critical := range []bool{true, false} {
+			name := "Ct" + board + "MinorFault"
+			if critical {
+				name = "Ct" + board + "CriticalFault"
+			}
+
+			oldCode := old.intField(name)
+			newCode := new.intField(name)
+			if oldCode == newCode {
+				continue
+			}
+
+			if faultActive(oldCode) {
+				events = append(events, FaultCleared{DeviceId: deviceId, Time: now, Board: board, Code: oldCode, Critical: critical})
+			}
+
+			if faultActive(newCode) {
+				events = append(events, FaultRaised{DeviceId: deviceId, Time: now, Board: board, Code: newCode, Critical: critical})
+			}
+		}
+	}
+
+	for i := 1; i <= rfSensorCount; i++ {
+		prefix := fmt.Sprintf("RFtempHumSensor%d", i)
+		if old.boolField(prefix+"Online") && !new.boolField(prefix+"Online") {
+			events = append(events, SensorOffline{DeviceId: deviceId, Time: now, Sensor: i, Name: new.stringField(prefix + "Name")})
+		}
+	}
+
+	for _, alert := range filterAlerts {
+		if !old.boolField(alert.field) && new.boolField(alert.field) {
+			events = append(events, FilterDue{DeviceId: deviceId, Time: now, Filter: alert.filter})
+		}
+	}
+
+	return events
+}
+
+// 0 means no fault and 255 means the board is not present
+func faultActive(code int) bool {
+	return code != 0 && code != 255
+}
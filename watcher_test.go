@@ -0,0 +1,132 @@
+package daikin_test
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/nbio/st"
+	"github.com/redgoose/daikin-skyport"
+)
+
+func TestWatcher(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/" + deviceId).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"mode":                      daikin.ModeCool,
+			"cspHome":                   22,
+			"hspHome":                   17.5,
+			"equipmentStatus":           daikin.EquipmentStatusCool,
+			"ctOutdoorCriticalFault":    0,
+			"RFtempHumSensor1Online":    true,
+			"RFtempHumSensor1Name":      "Bedroom",
+			"alertMediaAirFilterActive": false,
+		})
+
+	gock.New(urlBase).
+		Get("/deviceData/" + deviceId).
+		Reply(500)
+
+	gock.New(urlBase).
+		Get("/deviceData/" + deviceId).
+		Persist().
+		Reply(200).
+		JSON(map[string]interface{}{
+			"mode":                      daikin.ModeHeat,
+			"cspHome":                   22,
+			"hspHome":                   20,
+			"equipmentStatus":           daikin.EquipmentStatusHeat,
+			"ctOutdoorCriticalFault":    12,
+			"RFtempHumSensor1Online":    false,
+			"RFtempHumSensor1Name":      "Bedroom",
+			"alertMediaAirFilterActive": true,
+		})
+
+	d := daikin.New(email, password)
+	w := d.NewWatcher(deviceId)
+	w.Interval = 5 * time.Millisecond
+	w.Jitter = time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var events []daikin.Event
+	for event := range w.Run(ctx) {
+		events = append(events, event)
+		if len(events) == 7 {
+			cancel()
+		}
+	}
+
+	st.Expect(t, len(events) >= 7, true)
+	st.Expect(t, events[0].(daikin.PollFailed).DeviceId, deviceId)
+	st.Expect(t, events[1].(daikin.ModeChanged).New, daikin.ModeHeat)
+	st.Expect(t, events[2].(daikin.SetpointChanged).NewHeat, daikin.Celsius(20))
+	st.Expect(t, events[3].(daikin.EquipmentStatusChanged).New, daikin.EquipmentStatusHeat)
+	st.Expect(t, events[4], daikin.Event(daikin.FaultRaised{DeviceId: deviceId, Time: events[4].(daikin.FaultRaised).Time, Board: "Outdoor", Code: 12, Critical: true}))
+	st.Expect(t, events[5].(daikin.SensorOffline).Name, "Bedroom")
+	st.Expect(t, events[6].(daikin.FilterDue).Filter, "media")
+}
+
+func TestWatcherDefaultInterval(t *testing.T) {
+	defer gock.Off()
+
+	email := "test@test.com"
+	password := "mypassword"
+	accessToken := "foo"
+	deviceId := "0000000-0000-0000-0000-000000000000"
+
+	gock.New(urlBase).
+		Post("/users/auth/login").
+		JSON(map[string]string{"email": email, "password": password}).
+		Reply(200).
+		JSON(map[string]interface{}{"accessToken": accessToken, "accessTokenExpiresIn": 3600})
+
+	gock.New(urlBase).
+		Get("/deviceData/" + deviceId).
+		Persist().
+		Reply(500)
+
+	var requests int32
+	gock.Observe(func(req *http.Request, mock gock.Mock) {
+		if req.Method == http.MethodGet {
+			atomic.AddInt32(&requests, 1)
+		}
+	})
+	defer gock.Observe(nil)
+
+	d := daikin.New(email, password)
+	w := d.NewWatcher(deviceId)
+	w.Interval = 0
+	w.MaxBackoff = 0
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var events []daikin.Event
+	for event := range w.Run(ctx) {
+		events = append(events, event)
+	}
+
+	// a failing device is polled once, then waits for the default interval
+	st.Expect(t, atomic.LoadInt32(&requests), int32(1))
+	st.Expect(t, len(events), 1)
+	st.Expect(t, events[0].(daikin.PollFailed).DeviceId, deviceId)
+}